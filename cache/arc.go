@@ -0,0 +1,238 @@
+// arc.go contains the implementation of the ARCCache type, an Adaptive
+// Replacement Cache as described by Megiddo and Modha. It balances recency
+// and frequency by splitting the cache between entries seen once and entries
+// seen more than once, and tunes the split continuously from the hits it
+// observes on recently evicted keys.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// arcList identifies which of the four ARC lists an entry is on.
+type arcList int
+
+const (
+	arcT1 arcList = iota // Resident entries used once recently.
+	arcT2                // Resident entries used at least twice recently.
+	arcB1                // Ghost keys recently evicted from T1.
+	arcB2                // Ghost keys recently evicted from T2.
+)
+
+// arcEntry holds a key-value pair, or only a key while it is a ghost,
+// together with the list it is on.
+type arcEntry[K comparable, V any] struct {
+	key   K
+	value V
+	list  arcList
+	elem  *list.Element
+}
+
+// ARCStats is a snapshot of an ARCCache's adaptive state, for tuning.
+type ARCStats struct {
+	P  int // Target size of T1; grows while recency pays off and shrinks while frequency does.
+	T1 int // Resident entries used once recently.
+	T2 int // Resident entries used at least twice recently.
+	B1 int // Ghost keys evicted from T1.
+	B2 int // Ghost keys evicted from T2.
+}
+
+// ARCCache is a fixed-capacity cache implementing the Adaptive Replacement
+// Cache algorithm. Resident entries live on T1 if they were used once and on
+// T2 if they were used again; the keys of entries evicted from each are
+// remembered, without their values, on the ghost lists B1 and B2. A miss on a
+// ghost key shifts the target size p of T1 towards the list it came from, so
+// the cache adapts between recency-heavy and frequency-heavy workloads and
+// resists one-off scans. ARCCache is safe for concurrent use by multiple
+// goroutines.
+type ARCCache[K comparable, V any] struct {
+	capacity int                   // Maximum number of resident items; also the bound for ghosts.
+	p        int                   // Adaptive target size of T1.
+	lists    [4]*list.List         // T1, T2, B1 and B2, most recently used at the front.
+	dict     map[K]*arcEntry[K, V] // Map for quick access to resident and ghost entries.
+	mu       sync.Mutex            // Mutex to protect concurrent access to the cache.
+}
+
+// NewARC creates a new ARCCache with the given capacity.
+func NewARC[K comparable, V any](capacity int) *ARCCache[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+
+	c := &ARCCache[K, V]{
+		capacity: capacity,
+		dict:     make(map[K]*arcEntry[K, V], 2*capacity),
+	}
+	for i := range c.lists {
+		c.lists[i] = list.New()
+	}
+	return c
+}
+
+// Get retrieves the value associated with the given key. A hit moves the
+// entry to the front of T2.
+func (c *ARCCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok && (e.list == arcT1 || e.list == arcT2) {
+		c.move(e, arcT2)
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds or updates a key-value pair. A new key enters T1, unless it is
+// remembered on a ghost list, in which case p is adapted and it enters T2.
+// If the cache is full, an entry is evicted from T1 or T2 depending on p.
+func (c *ARCCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	if !ok {
+		c.admit(key, val)
+		return
+	}
+
+	switch e.list {
+	case arcB1:
+		c.p = minInt(c.capacity, c.p+maxInt(1, c.len(arcB2)/c.len(arcB1)))
+		c.replace(false)
+	case arcB2:
+		c.p = maxInt(0, c.p-maxInt(1, c.len(arcB1)/c.len(arcB2)))
+		c.replace(true)
+	}
+	e.value = val
+	c.move(e, arcT2)
+}
+
+// Delete removes key from the cache, forgetting it on the ghost lists as
+// well, and reports whether it was resident.
+func (c *ARCCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	if !ok {
+		return false
+	}
+	c.remove(e)
+	return e.list == arcT1 || e.list == arcT2
+}
+
+// Len returns the number of resident entries in the cache.
+func (c *ARCCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.len(arcT1) + c.len(arcT2)
+}
+
+// Close does nothing, as an ARCCache holds no background resources. It exists
+// to satisfy Cache.
+func (c *ARCCache[K, V]) Close() {}
+
+// Stats returns the current target size p and the lengths of the four lists.
+func (c *ARCCache[K, V]) Stats() ARCStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ARCStats{
+		P:  c.p,
+		T1: c.len(arcT1),
+		T2: c.len(arcT2),
+		B1: c.len(arcB1),
+		B2: c.len(arcB2),
+	}
+}
+
+// admit inserts a key that is on none of the lists at the front of T1, first
+// making room in the cache and trimming the ghost lists so that T1 and B1
+// together, and all four lists together, stay within their bounds.
+func (c *ARCCache[K, V]) admit(key K, val V) {
+	t1, b1 := c.len(arcT1), c.len(arcB1)
+	total := t1 + b1 + c.len(arcT2) + c.len(arcB2)
+
+	switch {
+	case t1+b1 >= c.capacity:
+		if t1 < c.capacity {
+			c.removeOldest(arcB1)
+			c.replace(false)
+		} else {
+			c.removeOldest(arcT1) // T1 alone fills the cache; drop its oldest without a ghost.
+		}
+	case total >= c.capacity:
+		if total >= 2*c.capacity {
+			c.removeOldest(arcB2)
+		}
+		c.replace(false)
+	}
+
+	e := &arcEntry[K, V]{key: key, value: val, list: arcT1}
+	e.elem = c.lists[arcT1].PushFront(e)
+	c.dict[key] = e
+}
+
+// replace evicts one resident entry if the cache is full, turning it into a
+// ghost: the oldest of T1 if T1 exceeds its target p, or meets it while the
+// key being admitted was found on B2, and otherwise the oldest of T2.
+func (c *ARCCache[K, V]) replace(inB2 bool) {
+	t1 := c.len(arcT1)
+	if t1+c.len(arcT2) < c.capacity {
+		return
+	}
+
+	from, to := arcT2, arcB2
+	if t1 > 0 && (t1 > c.p || (inB2 && t1 == c.p) || c.len(arcT2) == 0) {
+		from, to = arcT1, arcB1
+	}
+	e := c.lists[from].Back().Value.(*arcEntry[K, V])
+	var zero V
+	e.value = zero // Ghosts keep only the key.
+	c.move(e, to)
+}
+
+// move moves e to the front of list l.
+func (c *ARCCache[K, V]) move(e *arcEntry[K, V], l arcList) {
+	c.lists[e.list].Remove(e.elem)
+	e.list = l
+	e.elem = c.lists[l].PushFront(e)
+}
+
+// removeOldest forgets the least recently used entry of list l, if any.
+func (c *ARCCache[K, V]) removeOldest(l arcList) {
+	if back := c.lists[l].Back(); back != nil {
+		c.remove(back.Value.(*arcEntry[K, V]))
+	}
+}
+
+// remove deletes e from its list and the map.
+func (c *ARCCache[K, V]) remove(e *arcEntry[K, V]) {
+	c.lists[e.list].Remove(e.elem)
+	delete(c.dict, e.key)
+}
+
+// len returns the length of list l.
+func (c *ARCCache[K, V]) len(l arcList) int {
+	return c.lists[l].Len()
+}
+
+// minInt returns the smaller of a and b.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the larger of a and b.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestARCCache_PutGet tests basic put, get, update and delete operations.
+func TestARCCache_PutGet(t *testing.T) {
+	cache := NewARC[string, int](2)
+
+	cache.Put("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"a\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	cache.Put("a", 10)
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Fatalf("cache.Get(\"a\") after update = %v, %v; want %v, %v", v, ok, 10, true)
+	}
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed once")
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected an empty cache, got length %d", n)
+	}
+}
+
+// TestARCCache_Promotion tests that a second use moves an entry from T1 to T2.
+func TestARCCache_Promotion(t *testing.T) {
+	cache := NewARC[string, int](4)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+
+	if s := cache.Stats(); s.T1 != 1 || s.T2 != 1 {
+		t.Fatalf("Expected one entry on each of T1 and T2, got %+v", s)
+	}
+}
+
+// TestARCCache_GhostHitAdaptsP tests that a hit on a key recently evicted from T1 grows p and readmits the key to T2.
+func TestARCCache_GhostHitAdaptsP(t *testing.T) {
+	cache := NewARC[int, int](2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Get(2)    // T1 = [1], T2 = [2].
+	cache.Put(3, 3) // T1 exceeds p = 0, so 1 is evicted to B1.
+
+	if _, ok := cache.Get(1); ok {
+		t.Fatal("Expected 1 to be evicted")
+	}
+	if s := cache.Stats(); s.B1 != 1 || s.P != 0 {
+		t.Fatalf("Expected 1 on B1 and p = 0, got %+v", s)
+	}
+
+	cache.Put(1, 10)
+	s := cache.Stats()
+	if s.P != 1 || s.T1 != 1 || s.T2 != 1 || s.B2 != 1 {
+		t.Fatalf("Expected p = 1, 1 readmitted to T2 and 2 evicted to B2, got %+v", s)
+	}
+	if v, ok := cache.Get(1); !ok || v != 10 {
+		t.Fatalf("cache.Get(1) = %v, %v; want %v, %v", v, ok, 10, true)
+	}
+}
+
+// TestARCCache_ScanResistance tests that a one-off scan does not push out repeatedly used entries.
+func TestARCCache_ScanResistance(t *testing.T) {
+	cache := NewARC[int, int](10)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+		cache.Get(i)
+	}
+	for i := 100; i < 200; i++ {
+		cache.Put(i, i)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, ok := cache.Get(i); !ok {
+			t.Errorf("Expected hot key %d to survive the scan", i)
+		}
+	}
+}
+
+// TestARCCache_Bounds tests that the list sizes stay within the ARC invariants under a random workload.
+func TestARCCache_Bounds(t *testing.T) {
+	const capacity = 16
+	cache := NewARC[int, int](capacity)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(64)
+		switch rng.Intn(10) {
+		case 0:
+			cache.Delete(key)
+		case 1, 2, 3:
+			cache.Put(key, i)
+		default:
+			if _, ok := cache.Get(key); !ok {
+				cache.Put(key, i)
+			}
+		}
+
+		s := cache.Stats()
+		if s.T1+s.T2 > capacity || s.T1+s.B1 > capacity || s.T1+s.T2+s.B1+s.B2 > 2*capacity || s.P < 0 || s.P > capacity {
+			t.Fatalf("ARC invariant violated after %d operations: %+v", i+1, s)
+		}
+		if len(cache.dict) != s.T1+s.T2+s.B1+s.B2 {
+			t.Fatalf("Map holds %d keys but the lists hold %d", len(cache.dict), s.T1+s.T2+s.B1+s.B2)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// cache.go contains the Cache interface implemented by the caches in this
+// package.
+
+package cache
+
+// Cache is the basic interface shared by the caches in this package, so that
+// application code can swap eviction policies without changing call sites.
+type Cache[K comparable, V any] interface {
+	// Get retrieves the value associated with key and reports whether it was found.
+	Get(key K) (V, bool)
+	// Put adds or updates the value associated with key.
+	Put(key K, val V)
+	// Delete removes key and reports whether it was present.
+	Delete(key K) bool
+	// Len returns the number of entries in the cache.
+	Len() int
+	// Close releases any background resources held by the cache, such as
+	// janitor goroutines. It is safe to call more than once.
+	Close()
+}
+
+// Ensure the caches implement Cache at compile time.
+var (
+	_ Cache[string, int] = (*LRUCache[string, int])(nil)
+	_ Cache[string, int] = (*ShardedLRUCache[string, int])(nil)
+	_ Cache[string, int] = (*StringLRUCache[int])(nil)
+	_ Cache[string, int] = (*ScoredCache[string, int])(nil)
+	_ Cache[string, int] = (*PriorityCache[string, int])(nil)
+	_ Cache[string, int] = (*LFUCache[string, int])(nil)
+	_ Cache[string, int] = (*ARCCache[string, int])(nil)
+	_ Cache[string, int] = (*TwoQueueCache[string, int])(nil)
+	_ Cache[string, int] = (*SWRCache[string, int])(nil)
+	_ Cache[string, int] = (*WriteBehindCache[string, int])(nil)
+	_ Cache[string, int] = (*RecordingCache[string, int])(nil)
+	_ Cache[string, int] = (*MapCache[string, int])(nil)
+	_ Cache[string, int] = NopCache[string, int]{}
+)
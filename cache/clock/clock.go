@@ -0,0 +1,45 @@
+// Package clock abstracts the passage of time for the caches in package
+// cache, so that TTLs and background janitors can be driven by a fake clock
+// in tests instead of by sleeping.
+package clock
+
+import "time"
+
+// Clock tells the current time and creates timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that fires once after d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It reports false if the timer had
+	// already fired or been stopped.
+	Stop() bool
+}
+
+// Real returns the Clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+// realClock implements Clock with the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer adapts time.Timer to Timer.
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+func (t realTimer) Stop() bool          { return t.t.Stop() }
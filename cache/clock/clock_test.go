@@ -0,0 +1,21 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReal tests that the real clock follows the time package.
+func TestReal(t *testing.T) {
+	clk := Real()
+	before := time.Now()
+	if now := clk.Now(); now.Before(before) {
+		t.Fatalf("Expected Now at or after %v, got %v", before, now)
+	}
+
+	timer := clk.NewTimer(time.Millisecond)
+	<-timer.C()
+	if timer.Stop() {
+		t.Error("Expected Stop on a fired timer to report false")
+	}
+}
@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance is called, for
+// deterministic tests. Timers fire during the Advance that reaches their
+// deadline. Fake is safe for concurrent use by multiple goroutines.
+type Fake struct {
+	now    time.Time    // Current time.
+	timers []*fakeTimer // Timers that have not fired or been stopped.
+	mu     sync.Mutex   // Mutex to protect now and timers.
+}
+
+// NewFake creates a Fake clock set to start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// NewTimer creates a Timer that fires once the clock has been advanced by d.
+// A timer with a d of zero or less fires immediately.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTimer{f: f, when: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- f.now
+		return t
+	}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires every timer whose deadline
+// has been reached, in deadline order.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	for {
+		next := -1
+		for i, t := range f.timers {
+			if !t.when.After(f.now) && (next < 0 || t.when.Before(f.timers[next].when)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+		t := f.timers[next]
+		f.timers = append(f.timers[:next], f.timers[next+1:]...)
+		t.ch <- f.now // Buffered, and each timer fires at most once.
+	}
+}
+
+// Timers returns the number of timers waiting to fire. Tests use it to wait
+// until a goroutine under test has armed its timer before calling Advance.
+func (f *Fake) Timers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.timers)
+}
+
+// fakeTimer is a Timer created by a Fake clock.
+type fakeTimer struct {
+	f    *Fake
+	when time.Time
+	ch   chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+
+	for i, pending := range t.f.timers {
+		if pending == t {
+			t.f.timers = append(t.f.timers[:i], t.f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
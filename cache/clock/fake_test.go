@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFake_Advance tests that timers fire in deadline order once the clock reaches them.
+func TestFake_Advance(t *testing.T) {
+	start := time.Unix(1000, 0)
+	f := NewFake(start)
+	late := f.NewTimer(2 * time.Second)
+	early := f.NewTimer(time.Second)
+
+	f.Advance(500 * time.Millisecond)
+	select {
+	case <-early.C():
+		t.Fatal("Expected no timer to fire before its deadline")
+	default:
+	}
+
+	f.Advance(time.Second)
+	if got := <-early.C(); !got.Equal(start.Add(1500 * time.Millisecond)) {
+		t.Errorf("Expected the early timer to deliver the advanced time, got %v", got)
+	}
+	if n := f.Timers(); n != 1 {
+		t.Fatalf("Expected 1 pending timer, got %d", n)
+	}
+
+	f.Advance(time.Second)
+	<-late.C()
+	if f.Now() != start.Add(2500*time.Millisecond) {
+		t.Errorf("Expected Now to reflect all advances, got %v", f.Now())
+	}
+}
+
+// TestFake_Stop tests that a stopped timer never fires and that stopping reports whether it was pending.
+func TestFake_Stop(t *testing.T) {
+	f := NewFake(time.Unix(0, 0))
+	timer := f.NewTimer(time.Second)
+
+	if !timer.Stop() || timer.Stop() {
+		t.Fatal("Expected Stop to succeed only once")
+	}
+	f.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected a stopped timer not to fire")
+	default:
+	}
+
+	if immediate := f.NewTimer(0); immediate.Stop() {
+		t.Error("Expected a zero-duration timer to have fired already")
+	}
+}
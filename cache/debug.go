@@ -0,0 +1,64 @@
+// debug.go contains DebugJSON, which dumps the state of an LRUCache as JSON
+// for admin and debugging endpoints.
+
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// debugMaxEntries bounds the number of entries listed by DebugJSON so that a
+// large cache does not produce a huge payload.
+const debugMaxEntries = 100
+
+// DebugEntry describes a single cache entry in a DebugDump.
+type DebugEntry[K comparable] struct {
+	Key      K             `json:"key"`
+	Age      time.Duration `json:"age_ns"`   // Time since the value was last set.
+	Accesses uint64        `json:"accesses"` // Number of Get hits since the entry was inserted.
+	Weight   int64         `json:"weight,omitempty"`
+}
+
+// DebugDump is the document produced by DebugJSON.
+type DebugDump[K comparable] struct {
+	Stats     CacheStats      `json:"stats"`
+	Entries   []DebugEntry[K] `json:"entries"`   // Most recently used first.
+	Truncated bool            `json:"truncated"` // Whether entries were omitted to bound the payload.
+}
+
+// DebugJSON returns a JSON document describing the cache: its stats and, in
+// recency order, up to 100 entries with their key, age and access count. The
+// snapshot is taken atomically. An error is returned if the keys cannot be
+// encoded as JSON.
+func (c *LRUCache[K, V]) DebugJSON() ([]byte, error) {
+	return json.Marshal(c.debugDump(debugMaxEntries))
+}
+
+// debugDump takes a snapshot of the cache listing at most limit entries.
+func (c *LRUCache[K, V]) debugDump(limit int) DebugDump[K] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	n := len(c.dict)
+	if n > limit {
+		n = limit
+	}
+
+	dump := DebugDump[K]{
+		Stats:     c.stats(),
+		Entries:   make([]DebugEntry[K], 0, n),
+		Truncated: len(c.dict) > limit,
+	}
+	for i := c.entries[sentinel].next; i != sentinel && len(dump.Entries) < limit; i = c.entries[i].next {
+		e := &c.entries[i]
+		dump.Entries = append(dump.Entries, DebugEntry[K]{
+			Key:      e.key,
+			Age:      now.Sub(e.written),
+			Accesses: e.accesses,
+			Weight:   e.weight,
+		})
+	}
+	return dump
+}
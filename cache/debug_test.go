@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_DebugJSON tests that the JSON dump round-trips and reflects the current state.
+func TestLRUCache_DebugJSON(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.Put("a", 1)
+	clk.Advance(time.Second)
+	cache.Put("b", 2)
+	cache.Get("a")
+	cache.Get("a")
+	clk.Advance(time.Second)
+
+	data, err := cache.DebugJSON()
+	if err != nil {
+		t.Fatalf("DebugJSON() returned error: %v", err)
+	}
+
+	var dump DebugDump[string]
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("Failed to decode DebugJSON output: %v", err)
+	}
+
+	if dump.Stats != (CacheStats{Capacity: 4, Length: 2, LoadFactor: 0.5, Hits: 2, HitRatio: 1}) {
+		t.Errorf("Unexpected stats: %+v", dump.Stats)
+	}
+	if dump.Truncated {
+		t.Error("Expected dump not to be truncated")
+	}
+	want := []DebugEntry[string]{
+		{Key: "a", Age: 2 * time.Second, Accesses: 2},
+		{Key: "b", Age: time.Second, Accesses: 0},
+	}
+	if len(dump.Entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(dump.Entries))
+	}
+	for i := range want {
+		if dump.Entries[i] != want[i] {
+			t.Errorf("Entry %d = %+v; want %+v", i, dump.Entries[i], want[i])
+		}
+	}
+}
+
+// TestLRUCache_DebugJSONTruncated tests that the entry list is bounded.
+func TestLRUCache_DebugJSONTruncated(t *testing.T) {
+	cache := NewLRUCache[int, int](10)
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+
+	dump := cache.debugDump(3)
+	if !dump.Truncated || len(dump.Entries) != 3 {
+		t.Fatalf("Expected 3 entries and truncation, got %d entries, truncated=%v", len(dump.Entries), dump.Truncated)
+	}
+	if dump.Entries[0].Key != 9 {
+		t.Errorf("Expected most recently used key 9 first, got %d", dump.Entries[0].Key)
+	}
+	if dump.Stats.Length != 10 {
+		t.Errorf("Expected stats to cover all 10 entries, got %d", dump.Stats.Length)
+	}
+}
@@ -0,0 +1,94 @@
+// hash.go contains the key hashing used to distribute keys across shards.
+
+package cache
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+)
+
+// seed is the process-wide seed used when hashing formatted keys.
+var seed = maphash.MakeSeed()
+
+// hashKey returns a well-distributed hash of key. Strings, integers, floats
+// and booleans are hashed directly without allocating; floats are hashed by
+// value, so -0.0 and +0.0, which are equal keys, hash alike.
+//
+// Any other comparable type, such as a struct, falls back to hashing its %#v
+// representation. The fallback allocates on every call and hashes equal keys
+// alike only if they format alike, which fails for composite keys holding
+// -0.0 and +0.0 or interfaces with differing dynamic types. For such keys, a
+// ShardedLRUCache should be given its own Partitioner.
+func hashKey[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return fnv1a(k)
+	case bool:
+		if k {
+			return mix(1)
+		}
+		return mix(0)
+	case float32:
+		return hashFloat(float64(k))
+	case float64:
+		return hashFloat(k)
+	case int:
+		return mix(uint64(k))
+	case int8:
+		return mix(uint64(k))
+	case int16:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint8:
+		return mix(uint64(k))
+	case uint16:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case uintptr:
+		return mix(uint64(k))
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	fmt.Fprintf(&h, "%#v", key)
+	return h.Sum64()
+}
+
+// hashFloat hashes a float key by its bits, with -0.0 normalized to +0.0 as
+// they compare equal.
+func hashFloat(f float64) uint64 {
+	if f == 0 {
+		f = 0 // Also turns -0.0 into +0.0.
+	}
+	return mix(math.Float64bits(f))
+}
+
+// fnv1a returns the 64-bit FNV-1a hash of s.
+func fnv1a(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// mix scrambles the bits of an integer key (the splitmix64 finalizer) so that
+// sequential keys spread evenly across shards.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"math"
+	"testing"
+)
+
+// TestHashKey_Floats tests that equal float keys hash alike, including -0.0 and +0.0.
+func TestHashKey_Floats(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	if hashKey(negZero) != hashKey(0.0) {
+		t.Error("Expected -0.0 and +0.0 to hash alike as float64")
+	}
+	if hashKey(float32(negZero)) != hashKey(float32(0)) {
+		t.Error("Expected -0.0 and +0.0 to hash alike as float32")
+	}
+	if hashKey(1.5) == hashKey(2.5) {
+		t.Error("Expected distinct floats to hash differently")
+	}
+	if n := testing.AllocsPerRun(100, func() { hashKey(1.5) }); n != 0 {
+		t.Errorf("Expected hashing a float not to allocate, got %v allocations", n)
+	}
+}
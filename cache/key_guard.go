@@ -0,0 +1,120 @@
+// key_guard.go contains keyGuard, which orders read-through fills of a cache
+// against writes of the same key, so that a value read from a backing store
+// before a write is never cached after it.
+
+package cache
+
+import "sync"
+
+// keyState tracks the fills in flight and the queued writes of a single key.
+type keyState[V any] struct {
+	readers int    // Fills in flight.
+	gen     uint64 // Bumped by every write; a fill started under an older gen is dropped.
+	queued  int    // Writes queued but not yet applied to the store.
+	val     V      // Value of the latest queued write.
+	del     bool   // Whether the latest queued write is a delete.
+}
+
+// keyGuard coordinates read-through fills with writes. A reader calls begin
+// before reading the store and fill afterwards; a writer applies its change to
+// the cache through write once the store has it, or through enqueue if the
+// store write is deferred, followed by flushed once it is done. Only keys with
+// fills in flight or writes queued are tracked, so the guard stays small. It
+// is safe for concurrent use.
+type keyGuard[K comparable, V any] struct {
+	mu   sync.Mutex
+	keys map[K]*keyState[V]
+}
+
+// begin registers a fill of key and returns the generation to pass to fill.
+// If a write of key is queued, the store is behind: begin registers nothing
+// and returns queued as true together with the value of the latest queued
+// write and whether it stores one, which callers must use instead of reading
+// the store.
+func (g *keyGuard[K, V]) begin(key K) (gen uint64, val V, found, queued bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st := g.state(key)
+	if st.queued > 0 {
+		return 0, st.val, !st.del, true
+	}
+	st.readers++
+	return st.gen, val, false, false
+}
+
+// fill ends a fill of key started at gen, calling apply to store the value
+// read unless key was written since. apply runs with the guard's lock held.
+func (g *keyGuard[K, V]) fill(key K, gen uint64, apply func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st := g.keys[key]
+	if st.gen == gen {
+		apply()
+	}
+	st.readers--
+	g.release(key, st)
+}
+
+// write calls apply to change the cached entry for key and invalidates the
+// fills of key in flight. apply runs with the guard's lock held.
+func (g *keyGuard[K, V]) write(key K, apply func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if st, ok := g.keys[key]; ok {
+		st.gen++
+	}
+	apply()
+}
+
+// enqueue is like write for a store write that is deferred: until a matching
+// call to flushed, begin reports val, or the key as absent if del is true.
+// apply runs with the guard's lock held, so writes are queued in the order
+// their cache changes are applied.
+func (g *keyGuard[K, V]) enqueue(key K, val V, del bool, apply func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st := g.state(key)
+	st.gen++
+	st.queued++
+	st.val, st.del = val, del
+	apply()
+}
+
+// flushed records that a write of key queued by enqueue reached the store.
+func (g *keyGuard[K, V]) flushed(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st := g.keys[key]
+	if st.queued--; st.queued == 0 {
+		var zero V
+		st.val = zero // Do not keep the value reachable.
+	}
+	g.release(key, st)
+}
+
+// state returns the state of key, tracking it if it is not yet. The caller
+// must hold g.mu.
+func (g *keyGuard[K, V]) state(key K) *keyState[V] {
+	st, ok := g.keys[key]
+	if !ok {
+		if g.keys == nil {
+			g.keys = make(map[K]*keyState[V])
+		}
+		st = &keyState[V]{}
+		g.keys[key] = st
+	}
+	return st
+}
+
+// release stops tracking key once nothing refers to st. The caller must hold
+// g.mu.
+func (g *keyGuard[K, V]) release(key K, st *keyState[V]) {
+	if st.readers == 0 && st.queued == 0 {
+		delete(g.keys, key)
+	}
+}
@@ -0,0 +1,154 @@
+// lfu.go contains the implementation of the LFUCache type, a cache that evicts
+// the least frequently used entry. Entries are grouped into buckets of equal
+// access count, kept in a list ordered by count, so that Get, Put and eviction
+// all run in O(1) time.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lfuBucket holds the entries that have been used exactly freq times, most
+// recently used at the front.
+type lfuBucket struct {
+	freq  uint64
+	items *list.List
+}
+
+// lfuEntry holds a key-value pair together with its position in the bucket
+// list and within its bucket.
+type lfuEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *list.Element // Element of the bucket list holding the entry's bucket.
+	elem   *list.Element // Element of the bucket's item list holding the entry.
+}
+
+// LFUCache is a fixed-capacity cache that, when full, evicts the entry that has
+// been used least often, choosing the least recently used one among entries of
+// equal count. A one-off scan therefore cannot push out entries that are used
+// repeatedly, which makes it a better fit than LRUCache for workloads where
+// scan resistance matters more than recency. Counts never decay, so an entry
+// that was hot in the past keeps its place until hotter entries need the room.
+// LFUCache is safe for concurrent use by multiple goroutines.
+type LFUCache[K comparable, V any] struct {
+	capacity int                   // Maximum number of items the cache can hold.
+	buckets  *list.List            // Buckets in ascending order of access count.
+	dict     map[K]*lfuEntry[K, V] // Map for quick access to entries.
+	mu       sync.Mutex            // Mutex to protect concurrent access to the cache.
+}
+
+// NewLFUCache creates a new LFUCache with the given capacity.
+func NewLFUCache[K comparable, V any](capacity int) *LFUCache[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+
+	return &LFUCache[K, V]{
+		capacity: capacity,
+		buckets:  list.New(),
+		dict:     make(map[K]*lfuEntry[K, V], capacity),
+	}
+}
+
+// Get retrieves the value associated with the given key and counts a use of it.
+func (c *LFUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok {
+		c.touch(e)
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds or updates a key-value pair. Updating an existing key counts as a
+// use of it. A new key starts with a count of one; if the cache is full, the
+// least frequently used entry is evicted first.
+func (c *LFUCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok {
+		e.value = val
+		c.touch(e)
+		return
+	}
+
+	if len(c.dict) >= c.capacity {
+		c.evict()
+	}
+
+	front := c.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket).freq != 1 {
+		front = c.buckets.PushFront(&lfuBucket{freq: 1, items: list.New()})
+	}
+	e := &lfuEntry[K, V]{key: key, value: val, bucket: front}
+	e.elem = front.Value.(*lfuBucket).items.PushFront(e)
+	c.dict[key] = e
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *LFUCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// Len returns the number of entries in the cache.
+func (c *LFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.dict)
+}
+
+// Close does nothing, as an LFUCache holds no background resources. It exists
+// to satisfy Cache.
+func (c *LFUCache[K, V]) Close() {}
+
+// touch counts a use of e by moving it to the front of the bucket for the
+// next higher count, creating that bucket if needed.
+func (c *LFUCache[K, V]) touch(e *lfuEntry[K, V]) {
+	cur := e.bucket
+	b := cur.Value.(*lfuBucket)
+
+	next := cur.Next()
+	if next == nil || next.Value.(*lfuBucket).freq != b.freq+1 {
+		next = c.buckets.InsertAfter(&lfuBucket{freq: b.freq + 1, items: list.New()}, cur)
+	}
+
+	b.items.Remove(e.elem)
+	if b.items.Len() == 0 {
+		c.buckets.Remove(cur)
+	}
+	e.bucket = next
+	e.elem = next.Value.(*lfuBucket).items.PushFront(e)
+}
+
+// evict removes the least recently used entry among the least frequently used
+// ones.
+func (c *LFUCache[K, V]) evict() {
+	if front := c.buckets.Front(); front != nil {
+		c.remove(front.Value.(*lfuBucket).items.Back().Value.(*lfuEntry[K, V]))
+	}
+}
+
+// remove deletes e from the cache, dropping its bucket if it becomes empty.
+func (c *LFUCache[K, V]) remove(e *lfuEntry[K, V]) {
+	b := e.bucket.Value.(*lfuBucket)
+	b.items.Remove(e.elem)
+	if b.items.Len() == 0 {
+		c.buckets.Remove(e.bucket)
+	}
+	delete(c.dict, e.key)
+}
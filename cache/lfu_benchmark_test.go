@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// BenchmarkLFUCache_Put benchmarks inserts into a full LFUCache and LRUCache, so that every Put evicts.
+func BenchmarkLFUCache_Put(b *testing.B) {
+	caches := map[string]Cache[int, int]{
+		"LRU": NewLRUCache[int, int](1000),
+		"LFU": NewLFUCache[int, int](1000),
+	}
+	for _, name := range []string{"LRU", "LFU"} {
+		cache := caches[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				cache.Put(i, i)
+			}
+		})
+	}
+}
+
+// BenchmarkLFUCache_Get benchmarks hits on an LFUCache and an LRUCache.
+func BenchmarkLFUCache_Get(b *testing.B) {
+	caches := map[string]Cache[int, int]{
+		"LRU": NewLRUCache[int, int](1000),
+		"LFU": NewLFUCache[int, int](1000),
+	}
+	for _, name := range []string{"LRU", "LFU"} {
+		cache := caches[name]
+		for i := 0; i < 1000; i++ {
+			cache.Put(i, i)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				cache.Get(i % 1000)
+			}
+		})
+	}
+}
+
+// BenchmarkLFUCache_ScanWorkload benchmarks a workload of hot keys interleaved with one-off scans and
+// reports the hit ratio each policy achieves, showing LFU's scan resistance.
+func BenchmarkLFUCache_ScanWorkload(b *testing.B) {
+	for _, name := range []string{"LRU", "LFU"} {
+		b.Run(name, func(b *testing.B) {
+			var cache Cache[string, int] = NewLRUCache[string, int](100)
+			if name == "LFU" {
+				cache = NewLFUCache[string, int](100)
+			}
+			rng := rand.New(rand.NewSource(1))
+			var hits, gets int
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				key := "hot" + strconv.Itoa(rng.Intn(50))
+				if i%2 == 1 {
+					key = "scan" + strconv.Itoa(i) // Never repeated.
+				}
+				gets++
+				if _, ok := cache.Get(key); ok {
+					hits++
+				} else {
+					cache.Put(key, i)
+				}
+			}
+			b.ReportMetric(float64(hits)/float64(gets), "hits/op")
+		})
+	}
+}
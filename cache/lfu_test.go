@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestLFUCache_PutGet tests basic put, get, update and delete operations.
+func TestLFUCache_PutGet(t *testing.T) {
+	cache := NewLFUCache[string, int](2)
+
+	cache.Put("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"a\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	cache.Put("a", 10)
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Fatalf("cache.Get(\"a\") after update = %v, %v; want %v, %v", v, ok, 10, true)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatal("Expected a miss for an absent key")
+	}
+
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed once")
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected an empty cache, got length %d", n)
+	}
+}
+
+// TestLFUCache_EvictsLeastFrequent tests that the least used entry is evicted, with recency breaking ties.
+func TestLFUCache_EvictsLeastFrequent(t *testing.T) {
+	cache := NewLFUCache[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("c")
+
+	cache.Put("d", 4) // "b" has the lowest count.
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected \"b\" to be evicted")
+	}
+
+	cache.Put("e", 5) // "d" is the only entry used once.
+	if _, ok := cache.Get("d"); ok {
+		t.Fatal("Expected \"d\" to be evicted")
+	}
+
+	cache.Get("e") // Ties "e" with "c" at two uses; "c" was used less recently.
+	cache.Put("f", 6)
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("Expected \"c\" to be evicted as the least recently used of the least frequent")
+	}
+	for _, key := range []string{"a", "e", "f"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %q to be present", key)
+		}
+	}
+}
+
+// TestLFUCache_ScanResistance tests that a one-off scan does not push out repeatedly used entries.
+func TestLFUCache_ScanResistance(t *testing.T) {
+	cache := NewLFUCache[int, int](10)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+		cache.Get(i)
+	}
+	for i := 100; i < 200; i++ {
+		cache.Put(i, i)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, ok := cache.Get(i); !ok {
+			t.Errorf("Expected hot key %d to survive the scan", i)
+		}
+	}
+	if n := cache.Len(); n != 10 {
+		t.Errorf("Expected length 10, got %d", n)
+	}
+}
+
+// TestLFUCache_DeleteMiddleBucket tests that deleting the last entry of a bucket keeps the bucket list consistent.
+func TestLFUCache_DeleteMiddleBucket(t *testing.T) {
+	cache := NewLFUCache[string, int](3)
+	cache.Put("once", 1)
+	cache.Put("twice", 2)
+	cache.Get("twice")
+	cache.Put("thrice", 3)
+	cache.Get("thrice")
+	cache.Get("thrice")
+
+	cache.Delete("twice")
+	cache.Get("once") // Moves into the bucket for two uses, which no longer exists.
+	cache.Put("new", 4)
+	cache.Put("newer", 5) // Evicts "new", the only entry used once.
+
+	if _, ok := cache.Get("new"); ok {
+		t.Error("Expected \"new\" to be evicted")
+	}
+	for _, key := range []string{"once", "thrice", "newer"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %q to be present", key)
+		}
+	}
+}
+
+// TestLFUCache_Concurrency tests the cache's thread-safety by performing parallel reads, writes and deletes.
+func TestLFUCache_Concurrency(t *testing.T) {
+	cache := NewLFUCache[string, int](50)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := strconv.Itoa(i % 60)
+			cache.Put(key, i)
+			cache.Get(key)
+			if i%7 == 0 {
+				cache.Delete(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := cache.Len(); n > 50 {
+		t.Errorf("Expected at most 50 entries, got %d", n)
+	}
+}
@@ -1,31 +1,109 @@
 // lru.go contains the implementation of the LRUCache type, applying a
 // Least Recently Used (LRU) caching strategy. The LRUCache is safe for
 // concurrent use by multiple goroutines, leveraging a mutex to protect
-// shared state and a sync.Pool to minimize allocation overhead.
+// shared state. Entries live in a slice-backed arena and are linked by
+// integer indices, which avoids per-entry allocations and interface type
+// assertions and keeps entries close together in memory.
 
 package cache
 
 import (
-	"container/list"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+	"github.com/edast/go-utils/internal/random"
 )
 
-// entry holds a key-value pair for the cache. It is used internally by the LRUCache
-// to store cache items in a linked list.
+// sentinel is the arena index of the sentinel node of the recency list. Its
+// next field points at the most recently used entry and its prev field at the
+// least recently used one.
+const sentinel = 0
+
+// entry holds a key-value pair for the cache together with the arena indices
+// of its neighbours in the recency list. It is used internally by the LRUCache.
 type entry[K comparable, V any] struct {
-	key   K
-	value V
+	key      K
+	value    V
+	tags     []string
+	weight   int64
+	inserted time.Time     // When the key was inserted.
+	written  time.Time     // When the value was last set.
+	expires  time.Time     // When the entry expires; zero if it never does.
+	sliding  time.Duration // TTL restarted by every hit; zero unless stored WithSliding.
+	accesses uint64        // Number of Get hits since the entry was inserted.
+	pinned   bool          // Whether the entry is exempt from eviction; see Pin.
+	prev     int
+	next     int
 }
 
 // LRUCache implements a generic Least Recently Used (LRU) cache. It automatically
 // evicts the least recently accessed items to maintain a fixed size. The cache is
 // thread-safe, supporting concurrent access by multiple goroutines.
 type LRUCache[K comparable, V any] struct {
-	capacity int                 // Maximum number of items the cache can hold.
-	list     *list.List          // Ordered list to track the least recently used items.
-	dict     map[K]*list.Element // Map for quick access to list elements.
-	pool     sync.Pool           // Pool to reuse entry objects.
-	mu       sync.Mutex          // Mutex to protect concurrent access to the cache.
+	hits      uint64 // Number of Get calls that found their key; accessed atomically and kept first for 64-bit alignment.
+	misses    uint64 // Number of Get calls that did not find their key; accessed atomically.
+	evictions uint64 // Number of entries evicted to make room; accessed atomically.
+	expired   uint64 // Number of entries removed because their TTL elapsed; accessed atomically.
+
+	capacity int                       // Maximum number of items the cache can hold.
+	entries  []entry[K, V]             // Arena of entries; index 0 is the recency list sentinel.
+	dict     map[K]int                 // Map from key to the entry's arena index.
+	free     []int                     // Arena indices of removed entries available for reuse.
+	mu       sync.Mutex                // Mutex to protect concurrent access to the cache.
+	onEvict  func(key K, val V)        // Optional hook invoked with the lock held before an entry is evicted.
+	tags     map[string]map[K]struct{} // Index of tag to keys carrying it, allocated on first PutTagged.
+	weigh    func(key K, val V) int64  // Weighs entries in weighted mode; nil for count-based eviction.
+	budget   int64                     // Maximum total weight in weighted mode.
+	weight   int64                     // Total weight of all entries in weighted mode.
+	now      func() time.Time          // Clock used to timestamp entries; replaceable in tests.
+	clk      clock.Clock               // Clock driving the janitor; nil means the real clock.
+	onBatch  func([]KeyValue[K, V])    // Optional callback receiving the entries evicted by one operation.
+	evicted  []KeyValue[K, V]          // Entries evicted by the current operation, collected for onBatch.
+	evictFn  func(key K, val V)        // Optional callback invoked without the lock after an entry is evicted or expires.
+	pending  []KeyValue[K, V]          // Entries evicted by the current operation, collected for evictFn.
+	calls    map[K]*call[V]            // In-flight GetOrCompute computations, allocated on first use.
+	detached bool                      // Whether GetOrComputeContext loads outlive the caller that started them.
+	refresh  time.Duration             // Age after which a read triggers a background reload; 0 disables it.
+	reload   func(key K) (V, error)    // Loader used by refresh-ahead.
+	inReload map[K]struct{}            // Keys with a background reload in progress, allocated on first use.
+	reloads  sync.WaitGroup            // Tracks background reloads.
+	pins     int                       // Number of pinned entries.
+	janitor  chan struct{}             // Closed to stop the background janitor; nil if none is running.
+	interval time.Duration             // Sweep interval of the running janitor.
+	sweeping sync.WaitGroup            // Tracks the background janitor goroutine.
+	lifetime time.Duration             // Total lifetime of the entries counted in evictions.
+	batch    int                       // Entries evicted at once when a full cache needs room; 0 means 1.
+	version  uint64                    // Number of changes applied to the cache; guarded by mu.
+	changes  []CacheEvent[K, V]        // Recent changes for ChangesSince, oldest first.
+	logSize  int                       // Number of changes the change log retains at least; 0 disables it.
+	ttl      time.Duration             // Default TTL of entries stored without one; 0 means they never expire.
+	jitter   float64                   // Fraction by which the default TTL is randomly spread; 0 disables it.
+	rand     random.Source             // Source of TTL jitter; nil means random.Default.
+	negative *LRUCache[K, struct{}]    // Keys recorded by PutNegative; nil unless SetNegativeCache was called.
+}
+
+// KeyValue is a key-value pair removed from a cache, as passed to eviction
+// callbacks.
+type KeyValue[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// CacheStats is a point-in-time snapshot of an LRUCache's occupancy and
+// effectiveness, suitable for monitoring dashboards.
+type CacheStats struct {
+	Capacity    int     `json:"capacity"`         // Maximum number of items the cache can hold; zero in weighted mode, where it is not limited.
+	Budget      int64   `json:"budget,omitempty"` // Maximum total weight in weighted mode; zero otherwise.
+	Weight      int64   `json:"weight,omitempty"` // Total weight of the items in weighted mode; zero otherwise.
+	Length      int     `json:"length"`           // Number of items currently in the cache.
+	LoadFactor  float64 `json:"load_factor"`      // Length divided by Capacity, or Weight divided by Budget in weighted mode.
+	Hits        uint64  `json:"hits"`             // Lookups that found their key since the last ResetStats.
+	Misses      uint64  `json:"misses"`           // Lookups that did not find their key, including expired ones.
+	Evictions   uint64  `json:"evictions"`        // Entries evicted for capacity or weight since the last ResetStats.
+	Expirations uint64  `json:"expirations"`      // Entries removed because their TTL elapsed since the last ResetStats.
+	HitRatio    float64 `json:"hit_ratio"`        // Hits divided by Hits plus Misses; zero before any lookup.
 }
 
 // NewLRUCache creates a new instance of an LRUCache with the given capacity.
@@ -37,13 +115,9 @@ func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
 
 	return &LRUCache[K, V]{
 		capacity: capacity,
-		list:     list.New(),
-		dict:     make(map[K]*list.Element, capacity),
-		pool: sync.Pool{
-			New: func() interface{} {
-				return new(entry[K, V])
-			},
-		},
+		entries:  make([]entry[K, V], 1, capacity+1), // An empty circular list links the sentinel to itself.
+		dict:     make(map[K]int, capacity),
+		now:      time.Now,
 	}
 }
 
@@ -52,50 +126,725 @@ func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
 // Otherwise, it returns the zero value for V and false.
 func (c *LRUCache[K, V]) Get(key K) (V, bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, ok := c.dict[key]; ok {
-		c.list.MoveToFront(elem)
-		return elem.Value.(*entry[K, V]).value, true
+	if i, ok := c.lookup(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		return c.entries[i].value, true
 	}
+	atomic.AddUint64(&c.misses, 1)
 	var zero V
 	return zero, false
 }
 
 // Put adds a key-value pair to the cache. If the key already exists, its value
-// is updated. If adding a new key exceeds the cache's capacity, the least recently
-// used item is evicted. Put is safe to call from multiple goroutines.
+// is updated and any TTL it had is replaced by the cache's default TTL, if any.
+// If adding a new key exceeds the cache's capacity, the least recently used
+// item is evicted. Put is safe to call from multiple goroutines.
 func (c *LRUCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e := c.set(key, val); e != nil {
+		c.resetExpiry(e)
+		c.trim()
+	}
+}
+
+// TryPut adds or updates a key-value pair like Put, but never evicts to make
+// room for a new key: if key is absent and the cache is full, TryPut leaves
+// the cache untouched and returns false. In weighted mode the cache is full
+// when the new entry's weight would exceed the budget. Updating an existing
+// key always succeeds, unless the new value outweighs the whole budget.
+func (c *LRUCache[K, V]) TryPut(key K, val V) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.lookup(key); !ok {
+		if len(c.dict) >= c.capacity {
+			return false
+		}
+		if c.weigh != nil && c.weight+c.weigh(key, val) > c.budget {
+			return false
+		}
+	}
+	e := c.set(key, val)
+	if e == nil {
+		return false
+	}
+	c.resetExpiry(e)
+	c.trim()
+	return true
+}
+
+// UpdateIf atomically replaces the value for key with new if the key is present
+// and pred reports true for its current value. It returns whether the value was
+// replaced. A successful update marks the key as most recently used.
+func (c *LRUCache[K, V]) UpdateIf(key K, pred func(old V) bool, new V) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.lookup(key)
+	if !ok || !pred(c.entries[i].value) {
+		return false
+	}
+	if c.set(key, new) == nil {
+		return false
+	}
+	c.trim()
+	return true
+}
+
+// CompareAndSwap atomically replaces the value for key in c with new if the
+// key is present and its current value equals old, and reports whether it did.
+// It is UpdateIf with an equality predicate, for caches whose values are
+// comparable; use UpdateIf with a custom predicate otherwise. Like UpdateIf,
+// it keeps the entry's TTL.
+func CompareAndSwap[K, V comparable](c *LRUCache[K, V], key K, old, new V) bool {
+	return c.UpdateIf(key, func(cur V) bool { return cur == old }, new)
+}
+
+// Swap stores val for key like Put and returns the value it replaced. existed
+// reports whether the key was present; an expired entry counts as absent. The
+// key is marked as most recently used.
+func (c *LRUCache[K, V]) Swap(key K, val V) (previous V, existed bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if i, ok := c.lookup(key); ok {
+		previous, existed = c.entries[i].value, true
+	}
+	if e := c.set(key, val); e != nil {
+		c.resetExpiry(e)
+		c.trim()
+	}
+	return previous, existed
+}
+
+// Delete removes key from the cache and reports whether it was present. An
+// expired entry counts as absent. The removal is not reported to the eviction
+// callbacks, and the entry's slot is reused by a later insertion. A negative
+// entry for key is forgotten as well, but does not count as present.
+func (c *LRUCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.supersede(key)
+	if c.negative != nil {
+		c.negative.Delete(key)
+	}
+	i, ok := c.lookup(key)
+	if ok {
+		c.remove(i)
+	}
+	return ok
+}
+
+// Contains reports whether key is present without marking it as most
+// recently used or counting a hit or miss. An expired entry counts as absent.
+func (c *LRUCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, ok := c.lookup(key)
+	return ok
+}
+
+// Peek returns the value for key like Get, but without marking it as most
+// recently used or counting a hit or miss, so inspecting the cache does not
+// perturb its eviction order. An expired entry counts as absent.
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if i, ok := c.lookup(key); ok {
+		return c.entries[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Clear removes every entry from the cache. Like Resize, it reports the
+// removed entries to the eviction callbacks, from least to most recently used
+// and as a single batch; the change log records a single EventReset. The
+// arena keeps its allocated slots for reuse, but no longer references the
+// removed keys and values. Stats counters are left untouched.
+func (c *LRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.onEvict != nil || c.evictFn != nil || c.onBatch != nil {
+		for i := c.entries[sentinel].prev; i != sentinel; i = c.entries[i].prev {
+			e := &c.entries[i]
+			kv := KeyValue[K, V]{Key: e.key, Value: e.value}
+			if c.onEvict != nil {
+				c.onEvict(e.key, e.value)
+			}
+			if c.evictFn != nil {
+				c.pending = append(c.pending, kv)
+			}
+			if c.onBatch != nil {
+				c.evicted = append(c.evicted, kv)
+			}
+		}
+	}
+	for _, cl := range c.calls {
+		cl.stale = true
+	}
+	for i := range c.entries {
+		c.entries[i] = entry[K, V]{} // Also relinks the sentinel to itself.
+	}
+	c.entries = c.entries[:1]
+	c.dict = make(map[K]int, len(c.dict))
+	c.free = c.free[:0]
+	c.tags = nil
+	c.weight = 0
+	c.pins = 0
+	if c.negative != nil {
+		c.negative.Clear()
+	}
+
+	var key K
+	var val V
+	c.record(EventReset, key, val)
+}
+
+// Len returns the number of entries in the cache. Entries whose TTL has
+// elapsed are counted until they are accessed or removed by the janitor.
+func (c *LRUCache[K, V]) Len() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if elem, ok := c.dict[key]; ok {
-		elem.Value.(*entry[K, V]).value = val
-		c.list.MoveToFront(elem)
+	return len(c.dict)
+}
+
+// Keys returns the keys in the cache ordered from most to least recently used.
+// Like Len, it includes expired entries that have not been removed yet. The
+// returned slice is a copy owned by the caller.
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, len(c.dict))
+	for i := c.entries[sentinel].next; i != sentinel; i = c.entries[i].next {
+		keys = append(keys, c.entries[i].key)
+	}
+	return keys
+}
+
+// Range calls fn for each live entry, from most to least recently used, until
+// fn returns false. The entries are copied under the lock first, so fn sees a
+// consistent snapshot, runs without the lock held and may call back into the
+// cache. Expired entries are skipped. Range does not mark entries as used.
+func (c *LRUCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	now := c.now()
+	snapshot := make([]KeyValue[K, V], 0, len(c.dict))
+	for i := c.entries[sentinel].next; i != sentinel; i = c.entries[i].next {
+		e := &c.entries[i]
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			continue
+		}
+		snapshot = append(snapshot, KeyValue[K, V]{Key: e.key, Value: e.value})
+	}
+	c.mu.Unlock()
+
+	for _, kv := range snapshot {
+		if !fn(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
+
+// Resize changes the maximum number of entries the cache can hold. Shrinking
+// below the current length immediately evicts least recently used entries
+// down to newCapacity, reporting them to the eviction callbacks; growing only
+// raises the limit. An eviction batch set by SetEvictionBatch is lowered to
+// newCapacity if it would exceed it. Entries that remain stay warm, so Resize
+// can apply a new size from a configuration reload without rebuilding.
+func (c *LRUCache[K, V]) Resize(newCapacity int) {
+	if newCapacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.capacity = newCapacity
+	if c.batch > newCapacity {
+		c.batch = newCapacity
+	}
+	for len(c.dict) > c.capacity {
+		if !c.evict() {
+			break // Only pinned entries are left.
+		}
+	}
+}
+
+// SetEvictionBatch sets how many least recently used entries are evicted at
+// once when an insertion finds the cache full, instead of just one. Evicting
+// in batches amortizes the work of eviction under sustained insert pressure,
+// at the cost of running below capacity: once full, the cache holds between
+// capacity-n+1 and capacity entries. n must be between 1 and the capacity.
+func (c *LRUCache[K, V]) SetEvictionBatch(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n <= 0 || n > c.capacity {
+		panic("cache: eviction batch must be between 1 and the capacity")
+	}
+	c.batch = n
+}
+
+// OnEvictBatch sets fn to be called once per operation with all entries that
+// operation evicted or invalidated, instead of once per entry. This covers
+// capacity and weight evictions, TTL expiry, InvalidateTag and
+// InvalidateBefore, and Clear.
+// fn is called with the cache's lock held and must not call back into the
+// cache. It should be set before the cache is used.
+func (c *LRUCache[K, V]) OnEvictBatch(fn func(entries []KeyValue[K, V])) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onBatch = fn
+}
+
+// SetOnEvict sets fn to be called with the key and value of every entry that
+// leaves the cache because of capacity or weight eviction, because its TTL
+// elapsed or because of Clear. Explicit removals of single keys or tags, such
+// as Delete and InvalidateTag, are not reported.
+// fn is called after the cache's lock has been released, once the operation
+// that evicted the entry has completed, so it may block or call back into the
+// cache. It runs on the goroutine that triggered the eviction, which includes
+// the janitor. It should be set before the cache is used.
+func (c *LRUCache[K, V]) SetOnEvict(fn func(key K, val V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictFn = fn
+}
+
+// Stats returns a consistent snapshot of the cache's capacity, occupancy and
+// hit, miss, eviction and expiration counters. The counters are maintained
+// atomically by every operation, so they can be exported to a metrics system
+// by sampling Stats periodically.
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats()
+}
+
+// stats returns the cache's stats. The caller must hold c.mu.
+func (c *LRUCache[K, V]) stats() CacheStats {
+	s := CacheStats{
+		Length:      len(c.dict),
+		Hits:        atomic.LoadUint64(&c.hits),
+		Misses:      atomic.LoadUint64(&c.misses),
+		Evictions:   atomic.LoadUint64(&c.evictions),
+		Expirations: atomic.LoadUint64(&c.expired),
+	}
+	if c.weigh != nil {
+		s.Budget, s.Weight = c.budget, c.weight
+		s.LoadFactor = float64(c.weight) / float64(c.budget)
+	} else {
+		s.Capacity = c.capacity
+		s.LoadFactor = float64(len(c.dict)) / float64(c.capacity)
+	}
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		s.HitRatio = float64(s.Hits) / float64(lookups)
+	}
+	return s
+}
+
+// ResetStats zeroes the hit, miss, eviction and expiration counters and the evicted
+// lifetime average, so that periodic samples of Stats report the activity
+// since the previous sample.
+func (c *LRUCache[K, V]) ResetStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	atomic.StoreUint64(&c.hits, 0)
+	atomic.StoreUint64(&c.misses, 0)
+	atomic.StoreUint64(&c.evictions, 0)
+	atomic.StoreUint64(&c.expired, 0)
+	c.lifetime = 0
+}
+
+// AvgEvictedLifetime returns how long entries evicted for capacity or weight
+// since the last ResetStats had been in the cache on average, from insertion
+// to eviction. Updating a key does not restart its lifetime. A short average
+// suggests the cache is too small for its working set. It returns zero if no
+// entry has been evicted.
+func (c *LRUCache[K, V]) AvgEvictedLifetime() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := atomic.LoadUint64(&c.evictions)
+	if n == 0 {
+		return 0
+	}
+	return c.lifetime / time.Duration(n)
+}
+
+// PutTagged adds a key-value pair to the cache like Put and associates it with
+// the given tags, replacing any tags the key carried before. All entries that
+// share a tag can later be removed at once with InvalidateTag.
+func (c *LRUCache[K, V]) PutTagged(key K, val V, tags ...string) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e := c.set(key, val)
+	if e == nil {
 		return
 	}
+	c.resetExpiry(e)
+	c.untag(e)
+	c.tag(e, tags)
+	c.trim()
+}
+
+// InvalidateTag removes every entry carrying the given tag and returns the
+// number of entries removed. It does not scan the cache.
+func (c *LRUCache[K, V]) InvalidateTag(tag string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := c.tags[tag]
+	n := 0
+	for key := range keys {
+		if i, ok := c.dict[key]; ok {
+			c.drop(i)
+			n++
+		}
+	}
+	return n
+}
+
+// InvalidateBefore removes every entry whose value was last set before t and
+// returns the number of entries removed. Entries written at or after t are
+// kept. It scans the whole cache.
+func (c *LRUCache[K, V]) InvalidateBefore(t time.Time) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for i := c.entries[sentinel].next; i != sentinel; {
+		next := c.entries[i].next // remove clears the entry's links.
+		if c.entries[i].written.Before(t) {
+			c.drop(i)
+			n++
+		}
+		i = next
+	}
+	return n
+}
+
+// CacheOps gives access to an LRUCache while its lock is held by WithLock. It
+// is only valid for the duration of the WithLock callback and must not be
+// retained or used from another goroutine.
+type CacheOps[K comparable, V any] struct {
+	c *LRUCache[K, V]
+}
+
+// Get retrieves the value for key and marks it as most recently used, like
+// LRUCache.Get.
+func (o CacheOps[K, V]) Get(key K) (V, bool) {
+	if i, ok := o.c.lookup(key); ok {
+		o.c.touch(i)
+		return o.c.entries[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set adds or updates a key-value pair, like LRUCache.Put.
+func (o CacheOps[K, V]) Set(key K, val V) {
+	if e := o.c.set(key, val); e != nil {
+		o.c.resetExpiry(e)
+		o.c.trim()
+	}
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (o CacheOps[K, V]) Delete(key K) bool {
+	o.c.supersede(key)
+	i, ok := o.c.dict[key]
+	if ok {
+		o.c.remove(i)
+	}
+	return ok
+}
+
+// WithLock calls fn while holding the cache's lock, so that a compound
+// read-modify-write across several keys is applied atomically with respect to
+// all other cache operations. fn must not call methods on the cache itself, as
+// that would deadlock; it should use the provided CacheOps instead. Entries
+// evicted while fn runs are passed to the SetOnEvict callback once it returns.
+func (c *LRUCache[K, V]) WithLock(fn func(ops CacheOps[K, V])) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	fn(CacheOps[K, V]{c: c})
+}
+
+// lookup returns the arena index of key. An entry whose TTL has elapsed is
+// removed and reported as missing. The caller must hold c.mu.
+func (c *LRUCache[K, V]) lookup(key K) (int, bool) {
+	i, ok := c.dict[key]
+	if !ok {
+		return 0, false
+	}
+	if exp := c.entries[i].expires; !exp.IsZero() && !c.now().Before(exp) {
+		atomic.AddUint64(&c.expired, 1)
+		c.expire(i)
+		return 0, false
+	}
+	return i, true
+}
+
+// set inserts or updates key, marks it as most recently used and returns its
+// entry, evicting the least recently used item if the cache is full. The
+// returned pointer is only valid until the arena next grows. In weighted mode,
+// a value that outweighs the whole budget on its own is not stored, so that it
+// does not flush every other entry: set then removes any current entry for
+// key, which no longer holds the latest value, and returns nil. The caller
+// must hold c.mu.
+func (c *LRUCache[K, V]) set(key K, val V) *entry[K, V] {
+	c.supersede(key)
+	if c.negative != nil {
+		c.negative.Delete(key)
+	}
+	var w int64
+	if c.weigh != nil {
+		if w = c.weigh(key, val); w > c.budget {
+			if i, ok := c.dict[key]; ok {
+				c.remove(i)
+			}
+			return nil
+		}
+	}
+	if i, ok := c.dict[key]; ok {
+		e := &c.entries[i]
+		e.value = val
+		e.written = c.now()
+		c.reweigh(e, w)
+		c.moveToFront(i)
+		c.record(EventPut, key, val)
+		return e
+	}
+
+	if len(c.dict) >= c.capacity {
+		for n := 0; n == 0 || n < c.batch; n++ {
+			if !c.evict() {
+				break // Only pinned entries are left.
+			}
+		}
+	}
+
+	var i int
+	if n := len(c.free); n > 0 {
+		i = c.free[n-1]
+		c.free = c.free[:n-1]
+	} else {
+		c.entries = append(c.entries, entry[K, V]{})
+		i = len(c.entries) - 1
+	}
 
-	e := c.pool.Get().(*entry[K, V])
+	e := &c.entries[i]
 	e.key = key
 	e.value = val
+	e.inserted = c.now()
+	e.written = e.inserted
+	e.expires = c.defaultExpiry()
+	c.reweigh(e, w)
+	c.link(i)
+	c.dict[key] = i
+	c.record(EventPut, key, val)
+	return e
+}
+
+// tag records e under each of tags in the tag index.
+func (c *LRUCache[K, V]) tag(e *entry[K, V], tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	if c.tags == nil {
+		c.tags = make(map[string]map[K]struct{})
+	}
+	e.tags = append(e.tags[:0], tags...)
+	for _, t := range tags {
+		keys, ok := c.tags[t]
+		if !ok {
+			keys = make(map[K]struct{})
+			c.tags[t] = keys
+		}
+		keys[e.key] = struct{}{}
+	}
+}
 
-	if c.list.Len() >= c.capacity {
-		c.evict()
+// untag removes e from the tag index and clears its tags.
+func (c *LRUCache[K, V]) untag(e *entry[K, V]) {
+	for _, t := range e.tags {
+		if keys, ok := c.tags[t]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(c.tags, t)
+			}
+		}
 	}
+	e.tags = e.tags[:0]
+}
 
-	elem := c.list.PushFront(e)
-	c.dict[key] = elem
+// reweigh updates e's weight to w and the cache's total weight accordingly
+// after e's value was set. It does nothing unless the cache is in weighted
+// mode.
+func (c *LRUCache[K, V]) reweigh(e *entry[K, V], w int64) {
+	if c.weigh == nil {
+		return
+	}
+	c.weight += w - e.weight
+	e.weight = w
+}
+
+// trim evicts least recently used items until the total weight fits the
+// budget. It does nothing unless the cache is in weighted mode. As set never
+// stores an entry heavier than the budget, the entry just stored is not
+// evicted unless the unpinned entries alone cannot make room. The caller must
+// hold c.mu.
+func (c *LRUCache[K, V]) trim() {
+	for c.weigh != nil && c.weight > c.budget {
+		if !c.evict() {
+			break // Only pinned entries are left.
+		}
+	}
 }
 
-// evict removes the least recently used item from the cache.
-// It is called internally by Put when adding a new item would exceed
-// the cache's capacity. evict is safe to call from multiple goroutines.
-func (c *LRUCache[K, V]) evict() {
-	oldest := c.list.Back()
-	if oldest != nil {
-		oldEntry := oldest.Value.(*entry[K, V])
-		delete(c.dict, oldEntry.key)
-		c.list.Remove(oldest)
-		c.pool.Put(oldEntry)
+// evict removes the least recently used item that is not pinned from the
+// cache and reports whether there was one. It is called internally by Put when
+// adding a new item would exceed the cache's capacity. The caller must hold
+// c.mu.
+func (c *LRUCache[K, V]) evict() bool {
+	oldest := c.entries[sentinel].prev
+	for oldest != sentinel && c.entries[oldest].pinned {
+		oldest = c.entries[oldest].prev
+	}
+	if oldest == sentinel {
+		return false
+	}
+	atomic.AddUint64(&c.evictions, 1)
+	c.lifetime += c.now().Sub(c.entries[oldest].inserted)
+	c.expire(oldest)
+	return true
+}
+
+// expire removes the entry at arena index i because it was evicted or its TTL
+// elapsed, invoking the internal eviction hook first. The caller must hold
+// c.mu.
+func (c *LRUCache[K, V]) expire(i int) {
+	e := &c.entries[i]
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+	if c.evictFn != nil {
+		c.pending = append(c.pending, KeyValue[K, V]{Key: e.key, Value: e.value})
+	}
+	c.drop(i)
+}
+
+// drop removes the entry at arena index i as part of an eviction, expiry or
+// invalidation, recording it for the batch eviction callback. The caller must
+// hold c.mu.
+func (c *LRUCache[K, V]) drop(i int) {
+	if c.onBatch != nil {
+		e := &c.entries[i]
+		c.evicted = append(c.evicted, KeyValue[K, V]{Key: e.key, Value: e.value})
+	}
+	c.remove(i)
+}
+
+// emitEvicted passes the entries evicted by the current operation to the
+// batch eviction callback. It runs once per operation with the lock still
+// held.
+func (c *LRUCache[K, V]) emitEvicted() {
+	if len(c.evicted) == 0 {
+		return
+	}
+	batch := c.evicted
+	c.evicted = nil // The callback may retain the batch.
+	c.onBatch(batch)
+}
+
+// unlock ends a mutating operation. Mutating methods defer it right after
+// locking. It emits the operation's eviction batch, releases c.mu and only
+// then passes each entry the operation evicted to the eviction callback, so
+// that the callback may call back into the cache.
+func (c *LRUCache[K, V]) unlock() {
+	c.emitEvicted()
+	pending := c.pending
+	c.pending = nil // A reentrant operation collects its own evictions.
+	c.mu.Unlock()
+
+	for _, kv := range pending {
+		c.evictFn(kv.Key, kv.Value)
+	}
+}
+
+// remove unlinks the entry at arena index i from the cache, drops it from the
+// tag index and makes its slot available for reuse. The caller must hold c.mu.
+func (c *LRUCache[K, V]) remove(i int) {
+	e := &c.entries[i]
+	c.supersede(e.key)
+	c.record(EventDelete, e.key, e.value)
+	c.untag(e)
+	c.weight -= e.weight
+	if e.pinned {
+		c.pins--
+	}
+	delete(c.dict, e.key)
+	c.unlink(i)
+
+	// Clear the slot so the arena does not keep the key and value reachable.
+	tags := e.tags
+	*e = entry[K, V]{tags: tags[:0]}
+	c.free = append(c.free, i)
+}
+
+// link inserts the entry at arena index i at the front of the recency list.
+func (c *LRUCache[K, V]) link(i int) {
+	front := c.entries[sentinel].next
+	c.entries[i].prev = sentinel
+	c.entries[i].next = front
+	c.entries[front].prev = i
+	c.entries[sentinel].next = i
+}
+
+// unlink removes the entry at arena index i from the recency list.
+func (c *LRUCache[K, V]) unlink(i int) {
+	e := &c.entries[i]
+	c.entries[e.prev].next = e.next
+	c.entries[e.next].prev = e.prev
+}
+
+// touch records a hit on the entry at arena index i: it marks the entry as
+// most recently used, counts the access, restarts a sliding TTL and starts a
+// refresh-ahead reload if one is due. The caller must hold c.mu.
+func (c *LRUCache[K, V]) touch(i int) {
+	c.moveToFront(i)
+	e := &c.entries[i]
+	e.accesses++
+	if e.sliding > 0 {
+		e.expires = c.now().Add(e.sliding)
+	}
+	c.maybeReload(e)
+}
+
+// moveToFront marks the entry at arena index i as most recently used.
+func (c *LRUCache[K, V]) moveToFront(i int) {
+	if c.entries[sentinel].next == i {
+		return
 	}
+	c.unlink(i)
+	c.link(i)
 }
@@ -0,0 +1,60 @@
+// lru_batch.go contains the batch operations of LRUCache, which apply to many
+// keys while taking the cache's lock only once, to cut locking overhead on
+// hot paths that handle keys in bulk.
+
+package cache
+
+import "sync/atomic"
+
+// GetMany looks up keys like Get, in order, and returns the values found. Keys
+// that are missing or expired are absent from the result. Found keys are
+// marked as most recently used, the last one found ending up at the front.
+func (c *LRUCache[K, V]) GetMany(keys []K) map[K]V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	found := make(map[K]V, len(keys))
+	for _, key := range keys {
+		i, ok := c.lookup(key)
+		if !ok {
+			atomic.AddUint64(&c.misses, 1)
+			continue
+		}
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		found[key] = c.entries[i].value
+	}
+	return found
+}
+
+// PutMany stores each key-value pair like Put, in order, so the last pair ends
+// up most recently used. If the batch holds more distinct keys than the
+// capacity, the earlier ones are evicted by the later ones.
+func (c *LRUCache[K, V]) PutMany(items []KeyValue[K, V]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, kv := range items {
+		if e := c.set(kv.Key, kv.Value); e != nil {
+			c.resetExpiry(e)
+			c.trim()
+		}
+	}
+}
+
+// DeleteMany removes keys like Delete and returns the number that were
+// present.
+func (c *LRUCache[K, V]) DeleteMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for _, key := range keys {
+		c.supersede(key)
+		if i, ok := c.lookup(key); ok {
+			c.remove(i)
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package cache
+
+import "testing"
+
+// TestLRUCache_GetMany tests that found keys are returned and promoted while missing ones are absent.
+func TestLRUCache_GetMany(t *testing.T) {
+	cache := NewLRUCache[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	found := cache.GetMany([]string{"a", "missing", "b"})
+	if len(found) != 2 || found["a"] != 1 || found["b"] != 2 {
+		t.Fatalf("cache.GetMany() = %v; want map[a:1 b:2]", found)
+	}
+	if keys := cache.Keys(); keys[0] != "b" || keys[1] != "a" || keys[2] != "c" {
+		t.Errorf("Expected keys [b a c], got %v", keys)
+	}
+	if s := cache.Stats(); s.Hits != 2 || s.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", s)
+	}
+}
+
+// TestLRUCache_PutMany tests that a batch is stored in order and evicts like individual Puts.
+func TestLRUCache_PutMany(t *testing.T) {
+	cache := NewLRUCache[int, int](3)
+	var evicted []int
+	cache.SetOnEvict(func(key, _ int) { evicted = append(evicted, key) })
+
+	cache.PutMany([]KeyValue[int, int]{{1, 10}, {2, 20}, {3, 30}, {4, 40}})
+	if keys := cache.Keys(); len(keys) != 3 || keys[0] != 4 || keys[2] != 2 {
+		t.Fatalf("Expected keys [4 3 2], got %v", keys)
+	}
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Errorf("Expected 1 to be evicted, got %v", evicted)
+	}
+	if v, ok := cache.Get(4); !ok || v != 40 {
+		t.Errorf("cache.Get(4) = %v, %v; want %v, %v", v, ok, 40, true)
+	}
+}
+
+// TestLRUCache_DeleteMany tests that only present keys are counted as deleted.
+func TestLRUCache_DeleteMany(t *testing.T) {
+	cache := NewLRUCache[int, int](4)
+	cache.PutMany([]KeyValue[int, int]{{1, 1}, {2, 2}, {3, 3}})
+
+	if n := cache.DeleteMany([]int{1, 3, 5, 1}); n != 2 {
+		t.Fatalf("Expected 2 keys deleted, got %d", n)
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != 2 {
+		t.Errorf("Expected keys [2], got %v", keys)
+	}
+}
@@ -10,6 +10,7 @@ import (
 func BenchmarkLRUCache_Put(b *testing.B) {
 	cache := NewLRUCache[int, string](b.N) // Use b.N as the capacity to avoid evictions
 
+	b.ReportAllocs()
 	b.ResetTimer() // Reset the timer to exclude setup time from the benchmark
 
 	for i := 0; i < b.N; i++ {
@@ -24,6 +25,7 @@ func BenchmarkLRUCache_Get(b *testing.B) {
 		cache.Put(i, strconv.Itoa(i))
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -35,6 +37,7 @@ func BenchmarkLRUCache_Get(b *testing.B) {
 func BenchmarkLRUCache_PutGet(b *testing.B) {
 	cache := NewLRUCache[int, string](b.N)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -49,6 +52,7 @@ func BenchmarkLRUCache_Concurrent(b *testing.B) {
 
 	var keyCounter int64 // Atomic counter to generate unique keys
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	b.RunParallel(func(pb *testing.PB) {
@@ -63,3 +67,53 @@ func BenchmarkLRUCache_Concurrent(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkLRUCache_PutEvictBatch benchmarks inserts into a full cache that evicts one entry at a time
+// against one that evicts 1% of its capacity at once.
+func BenchmarkLRUCache_PutEvictBatch(b *testing.B) {
+	for _, batch := range []int{1, 100} {
+		b.Run("Batch"+strconv.Itoa(batch), func(b *testing.B) {
+			cache := NewLRUCache[int, int](10000)
+			cache.SetEvictionBatch(batch)
+			for i := 0; i < 10000; i++ {
+				cache.Put(-i-1, i) // Fill the cache so every insert below needs room.
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				cache.Put(i, i)
+			}
+		})
+	}
+}
+
+// BenchmarkLRUCache_PutMany benchmarks inserting 500 keys one Put at a time against a single PutMany.
+func BenchmarkLRUCache_PutMany(b *testing.B) {
+	items := make([]KeyValue[int, int], 500)
+	for i := range items {
+		items[i] = KeyValue[int, int]{Key: i, Value: i}
+	}
+
+	b.Run("Put", func(b *testing.B) {
+		cache := NewLRUCache[int, int](1000)
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			for _, kv := range items {
+				cache.Put(kv.Key, kv.Value)
+			}
+		}
+	})
+	b.Run("PutMany", func(b *testing.B) {
+		cache := NewLRUCache[int, int](1000)
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			cache.PutMany(items)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// lru_changes.go contains the LRUCache change log, which lets a cache be
+// persisted incrementally by replaying only the operations applied since the
+// last checkpoint.
+
+package cache
+
+// CacheEventOp identifies the kind of change recorded in a CacheEvent.
+type CacheEventOp int
+
+// Kinds of change recorded in the change log.
+const (
+	EventPut    CacheEventOp = iota // The key was inserted or its value was updated.
+	EventDelete                     // The key was removed, explicitly or by eviction or expiry.
+	EventReset                      // All keys were removed; the events that follow rebuild the cache from scratch.
+)
+
+// CacheEvent is a single change applied to an LRUCache, as returned by
+// ChangesSince.
+type CacheEvent[K comparable, V any] struct {
+	Op      CacheEventOp
+	Key     K
+	Value   V      // The stored value for EventPut, the removed one for EventDelete.
+	Version uint64 // Version of the cache once the change was applied.
+}
+
+// SetChangeLog enables the change log used by ChangesSince and bounds it to
+// retain at least the n most recent changes; n of zero disables it and
+// releases the retained log. Every change bumps the cache's version whether
+// or not the log is enabled. n must not be negative.
+func (c *LRUCache[K, V]) SetChangeLog(n int) {
+	if n < 0 {
+		panic("cache: change log size must not be negative")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logSize = n
+	if n == 0 {
+		c.changes = nil
+	}
+}
+
+// ChangesSince returns the changes applied to the cache after version, oldest
+// first, and the current version to pass to the next call. Applying the
+// changes in order to a copy that was current at version brings it up to
+// date; version 0 is the empty cache.
+//
+// If the change log no longer reaches back to version, because it was
+// truncated, disabled or version is unknown, ChangesSince falls back to a full
+// snapshot: an EventReset followed by an EventPut for every live entry, from
+// least to most recently used. TTLs are not part of the events.
+func (c *LRUCache[K, V]) ChangesSince(version uint64) ([]CacheEvent[K, V], uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version == c.version {
+		return nil, c.version
+	}
+	if n := len(c.changes); n > 0 && version < c.version && version+1 >= c.changes[0].Version {
+		since := c.changes[version+1-c.changes[0].Version:] // Versions in the log are consecutive.
+		return append([]CacheEvent[K, V](nil), since...), c.version
+	}
+	return c.snapshot(), c.version
+}
+
+// snapshot returns the events that rebuild the cache from scratch. The caller
+// must hold c.mu.
+func (c *LRUCache[K, V]) snapshot() []CacheEvent[K, V] {
+	events := make([]CacheEvent[K, V], 0, len(c.dict)+1)
+	events = append(events, CacheEvent[K, V]{Op: EventReset, Version: c.version})
+
+	now := c.now()
+	for i := c.entries[sentinel].prev; i != sentinel; i = c.entries[i].prev {
+		e := &c.entries[i]
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			continue
+		}
+		events = append(events, CacheEvent[K, V]{Op: EventPut, Key: e.key, Value: e.value, Version: c.version})
+	}
+	return events
+}
+
+// record bumps the cache's version and appends the change to the change log
+// if it is enabled. Once the log holds twice its bound, the older half is
+// discarded, which keeps appends amortized O(1). The caller must hold c.mu.
+func (c *LRUCache[K, V]) record(op CacheEventOp, key K, val V) {
+	c.version++
+	if c.logSize == 0 {
+		return
+	}
+	c.changes = append(c.changes, CacheEvent[K, V]{Op: op, Key: key, Value: val, Version: c.version})
+	if len(c.changes) >= 2*c.logSize {
+		c.changes = append(c.changes[:0], c.changes[len(c.changes)-c.logSize:]...)
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import "testing"
+
+// TestLRUCache_ChangesSince tests that the changes applied after a version are returned in order.
+func TestLRUCache_ChangesSince(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.SetChangeLog(10)
+
+	cache.Put("a", 1)
+	events, v1 := cache.ChangesSince(0)
+	if v1 != 1 || len(events) != 1 || events[0] != (CacheEvent[string, int]{Op: EventPut, Key: "a", Value: 1, Version: 1}) {
+		t.Fatalf("cache.ChangesSince(0) = %+v, %d", events, v1)
+	}
+
+	cache.Put("b", 2)
+	cache.Put("c", 3) // Evicts "a".
+	cache.Delete("b")
+	events, v2 := cache.ChangesSince(v1)
+	want := []CacheEvent[string, int]{
+		{Op: EventPut, Key: "b", Value: 2, Version: 2},
+		{Op: EventDelete, Key: "a", Value: 1, Version: 3},
+		{Op: EventPut, Key: "c", Value: 3, Version: 4},
+		{Op: EventDelete, Key: "b", Value: 2, Version: 5},
+	}
+	if v2 != 5 || len(events) != len(want) {
+		t.Fatalf("cache.ChangesSince(%d) = %+v, %d; want %+v, 5", v1, events, v2, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("Event %d = %+v; want %+v", i, events[i], want[i])
+		}
+	}
+
+	if events, v := cache.ChangesSince(v2); len(events) != 0 || v != v2 {
+		t.Errorf("Expected no changes since the current version, got %+v, %d", events, v)
+	}
+}
+
+// TestLRUCache_ChangesSinceTruncated tests that a version older than the retained log yields a full snapshot.
+func TestLRUCache_ChangesSinceTruncated(t *testing.T) {
+	cache := NewLRUCache[int, int](3)
+	cache.SetChangeLog(2)
+
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i*10)
+	}
+
+	events, v := cache.ChangesSince(1)
+	if v != 7 { // Five puts and two evictions.
+		t.Fatalf("Expected version 7, got %d", v)
+	}
+	if len(events) != 4 || events[0].Op != EventReset {
+		t.Fatalf("Expected a reset followed by 3 puts, got %+v", events)
+	}
+	for i, key := range []int{2, 3, 4} {
+		if e := events[i+1]; e.Op != EventPut || e.Key != key || e.Value != key*10 {
+			t.Errorf("Snapshot event %d = %+v; want a put of %d", i+1, e, key)
+		}
+	}
+
+	// The most recent changes are still served from the log.
+	if events, _ := cache.ChangesSince(v - 1); len(events) != 1 || events[0].Op != EventPut || events[0].Key != 4 {
+		t.Errorf("Expected the last put from the log, got %+v", events)
+	}
+}
+
+// TestLRUCache_ChangesSinceDisabled tests that without a change log every call yields a full snapshot.
+func TestLRUCache_ChangesSinceDisabled(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+
+	events, v := cache.ChangesSince(0)
+	if v != 1 || len(events) != 2 || events[0].Op != EventReset || events[1].Key != "a" {
+		t.Fatalf("cache.ChangesSince(0) = %+v, %d; want a snapshot at version 1", events, v)
+	}
+}
+
+// TestLRUCache_ChangesSinceClear tests that Clear is logged as a reset.
+func TestLRUCache_ChangesSinceClear(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.SetChangeLog(10)
+	cache.Put("a", 1)
+	_, v := cache.ChangesSince(0)
+
+	cache.Clear()
+	cache.Put("b", 2)
+	events, _ := cache.ChangesSince(v)
+	if len(events) != 2 || events[0].Op != EventReset || events[1].Key != "b" {
+		t.Errorf("Expected a reset followed by a put of \"b\", got %+v", events)
+	}
+}
@@ -0,0 +1,198 @@
+// lru_compute.go contains the compute-style mutations of LRUCache:
+// GetOrCompute, which fills a missing key from a compute function while making
+// sure concurrent callers for the same key share a single computation, and
+// Compute, an atomic read-modify-write of a single key.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// errComputePanicked is returned to callers waiting on a computation whose
+// compute function panicked.
+var errComputePanicked = errors.New("cache: compute function panicked")
+
+// call is an in-flight GetOrCompute computation. Callers for the same key wait
+// on done and then read value and err.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	stale bool // Whether key was written while computing; guarded by the cache's lock.
+}
+
+// GetOrCompute returns the value for key if it is cached. Otherwise it calls
+// compute, caches the value it returns and returns it. Concurrent callers for
+// the same missing key wait for a single call to compute and all receive its
+// result; callers for other keys are not blocked while compute runs, as the
+// cache's lock is not held during the computation. If compute returns an
+// error, nothing is cached and every waiting caller receives that error. If
+// key is written, deleted or invalidated while compute runs, the computed
+// value is still returned to the waiting callers but not cached, so that it
+// does not undo the newer change. An entry whose TTL has elapsed is missing too, so when a hot key expires
+// only one caller reloads it. A loader keyed like func(K) (V, error) is
+// passed as a closure over key.
+func (c *LRUCache[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if i, ok := c.lookup(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		val := c.entries[i].value
+		c.unlock()
+		return val, nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+	if cl, ok := c.calls[key]; ok {
+		c.unlock()
+		<-cl.done
+		return cl.value, cl.err
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	if c.calls == nil {
+		c.calls = make(map[K]*call[V])
+	}
+	c.calls[key] = cl
+	c.unlock()
+
+	c.compute(key, cl, compute)
+	return cl.value, cl.err
+}
+
+// GetOrComputeContext is like GetOrCompute, but passes ctx to compute so that
+// its deadline and cancellation reach the load, and stops waiting when ctx is
+// done, returning ctx.Err(). A cached value is returned even if ctx is
+// already done. Callers joining a load started by another caller share its
+// result, which may be that caller's context error.
+//
+// By default the caller that starts a load runs compute itself and waits for
+// it. With SetDetachedLoads(true), compute runs on its own goroutine with a
+// context that keeps ctx's values but is never cancelled, so it completes and
+// populates the cache even if every caller waiting for it gives up; compute
+// should then enforce its own timeout.
+func (c *LRUCache[K, V]) GetOrComputeContext(ctx context.Context, key K, compute func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	if i, ok := c.lookup(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		val := c.entries[i].value
+		c.unlock()
+		return val, nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call[V]{done: make(chan struct{})}
+		if c.calls == nil {
+			c.calls = make(map[K]*call[V])
+		}
+		c.calls[key] = cl
+	}
+	detached := c.detached
+	c.unlock()
+
+	switch {
+	case !ok && detached:
+		go c.compute(key, cl, func() (V, error) { return compute(detachedContext{ctx}) })
+	case !ok:
+		c.compute(key, cl, func() (V, error) { return compute(ctx) })
+		return cl.value, cl.err
+	}
+
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// SetDetachedLoads sets whether loads started by GetOrComputeContext run on
+// their own goroutine, detached from the cancellation of the caller that
+// started them. See GetOrComputeContext.
+func (c *LRUCache[K, V]) SetDetachedLoads(detached bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.detached = detached
+}
+
+// detachedContext carries the values of its parent but has no deadline and is
+// never cancelled, so that a detached load outlives its callers.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (d detachedContext) Value(key any) any         { return d.parent.Value(key) }
+
+// Compute atomically reads the value for key, passes it to fn and stores the
+// value fn returns. old is the current value and existed reports whether key
+// was present; an expired entry counts as absent. If fn returns keep as false,
+// key is deleted instead, without notifying the eviction callbacks. Compute
+// returns the stored value and whether key is now present. A stored key is
+// marked as most recently used and keeps its TTL, if any; a new key gets the
+// cache's default TTL. fn runs with the cache's lock held and must not call
+// back into the cache.
+func (c *LRUCache[K, V]) Compute(key K, fn func(old V, existed bool) (V, bool)) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var old V
+	i, existed := c.lookup(key)
+	if existed {
+		old = c.entries[i].value
+	}
+
+	val, keep := fn(old, existed)
+	if !keep {
+		if existed {
+			c.remove(i)
+		}
+		var zero V
+		return zero, false
+	}
+	if c.set(key, val) == nil {
+		var zero V
+		return zero, false
+	}
+	c.trim()
+	return val, true
+}
+
+// compute runs fn for the in-flight call cl, then caches a successful result
+// and releases the callers waiting on cl. The result is stored under the same
+// lock acquisition that retires the call, so no caller can miss both.
+func (c *LRUCache[K, V]) compute(key K, cl *call[V], fn func() (V, error)) {
+	cl.err = errComputePanicked // Overwritten unless fn panics.
+	defer func() {
+		c.mu.Lock()
+		defer c.unlock()
+
+		if cl.err == nil && !cl.stale {
+			if e := c.set(key, cl.value); e != nil {
+				c.resetExpiry(e)
+				c.trim()
+			}
+		}
+		delete(c.calls, key)
+		close(cl.done)
+	}()
+
+	cl.value, cl.err = fn()
+}
+
+// supersede marks the computation in flight for key, if any, as stale, so
+// that its result does not overwrite a change made meanwhile. The caller must
+// hold c.mu.
+func (c *LRUCache[K, V]) supersede(key K) {
+	if cl, ok := c.calls[key]; ok {
+		cl.stale = true
+	}
+}
@@ -0,0 +1,362 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_GetOrComputeHit tests that a cached value is returned without calling compute.
+func TestLRUCache_GetOrComputeHit(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+
+	v, err := cache.GetOrCompute("a", func() (int, error) {
+		t.Fatal("Expected compute not to be called for a cached key")
+		return 0, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("cache.GetOrCompute(\"a\") = %d, %v; want %d, nil", v, err, 1)
+	}
+}
+
+// TestLRUCache_GetOrComputeOnce tests that concurrent callers for a missing key share one computation.
+func TestLRUCache_GetOrComputeOnce(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	var calls int64
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		atomic.AddInt64(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+	var started, wg sync.WaitGroup
+	started.Add(callers)
+	wg.Add(callers)
+	results := make([]int, callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			results[i], _ = cache.GetOrCompute("k", compute)
+		}(i)
+	}
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Fatalf("Expected compute to run once, ran %d times", n)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Fatalf("Caller %d got %d; want %d", i, v, 42)
+		}
+	}
+	if v, ok := cache.Get("k"); !ok || v != 42 {
+		t.Fatalf("cache.Get(\"k\") = %d, %v; want %d, %v", v, ok, 42, true)
+	}
+}
+
+// TestLRUCache_GetOrComputeExpired tests that concurrent callers for an expired hot key share one reload.
+func TestLRUCache_GetOrComputeExpired(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+	cache.PutWithTTL("hot", 1, time.Second)
+	clk.Advance(time.Second)
+
+	var calls int64
+	release := make(chan struct{})
+	loader := func(key string) (int, error) {
+		atomic.AddInt64(&calls, 1)
+		<-release
+		return len(key), nil
+	}
+
+	const callers = 10
+	var started, wg sync.WaitGroup
+	started.Add(callers)
+	wg.Add(callers)
+	results := make([]int, callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			results[i], _ = cache.GetOrCompute("hot", func() (int, error) { return loader("hot") })
+		}(i)
+	}
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Fatalf("Expected the loader to run once, ran %d times", n)
+	}
+	for i, v := range results {
+		if v != 3 {
+			t.Fatalf("Caller %d got %d; want %d", i, v, 3)
+		}
+	}
+}
+
+// TestLRUCache_GetOrComputeRacingWrites tests that a write made while compute runs is not undone by the computed value.
+func TestLRUCache_GetOrComputeRacingWrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(c *LRUCache[string, int])
+		want  int
+		found bool
+	}{
+		{"Put", func(c *LRUCache[string, int]) { c.Put("k", 2) }, 2, true},
+		{"PutThenDelete", func(c *LRUCache[string, int]) { c.Put("k", 2); c.Delete("k") }, 0, false},
+		{"Delete", func(c *LRUCache[string, int]) { c.Delete("k") }, 0, false},
+		{"InvalidateTag", func(c *LRUCache[string, int]) { c.PutTagged("k", 2, "t"); c.InvalidateTag("t") }, 0, false},
+		{"Clear", func(c *LRUCache[string, int]) { c.Clear() }, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewLRUCache[string, int](2)
+			started, release := make(chan struct{}), make(chan struct{})
+			done := make(chan int)
+			go func() {
+				v, _ := cache.GetOrCompute("k", func() (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+				done <- v
+			}()
+
+			<-started
+			tt.write(cache)
+			close(release)
+			if v := <-done; v != 1 {
+				t.Fatalf("Expected the caller to receive the computed value, got %d", v)
+			}
+			if v, ok := cache.Get("k"); ok != tt.found || v != tt.want {
+				t.Errorf("cache.Get(\"k\") = %d, %v; want %d, %v", v, ok, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+// TestLRUCache_GetOrComputeKeysIndependent tests that a slow computation does not block other keys.
+func TestLRUCache_GetOrComputeKeysIndependent(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.GetOrCompute("slow", func() (int, error) {
+			<-release
+			return 1, nil
+		})
+	}()
+
+	// "fast" must complete while "slow" is still computing.
+	v, err := cache.GetOrCompute("fast", func() (int, error) { return 2, nil })
+	if err != nil || v != 2 {
+		t.Fatalf("cache.GetOrCompute(\"fast\") = %d, %v; want %d, nil", v, err, 2)
+	}
+	close(release)
+	<-done
+}
+
+// TestLRUCache_GetOrComputeError tests that an error is shared by waiters and nothing is cached.
+func TestLRUCache_GetOrComputeError(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	boom := errors.New("boom")
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		<-release
+		return 0, boom
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := cache.GetOrCompute("k", compute)
+		errs <- err
+	}()
+	// Wait for the first call to be in flight so the second one joins it.
+	for {
+		cache.mu.Lock()
+		_, inflight := cache.calls["k"]
+		cache.mu.Unlock()
+		if inflight {
+			break
+		}
+	}
+	go func() {
+		_, err := cache.GetOrCompute("k", compute)
+		errs <- err
+	}()
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, boom) {
+			t.Fatalf("Expected %v, got %v", boom, err)
+		}
+	}
+	if _, ok := cache.Get("k"); ok {
+		t.Fatal("Expected a failed computation not to be cached")
+	}
+
+	// A later call computes again.
+	v, err := cache.GetOrCompute("k", func() (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Fatalf("cache.GetOrCompute(\"k\") = %d, %v; want %d, nil", v, err, 7)
+	}
+}
+
+// ctxKey is a context key used to check that values reach a detached load.
+type ctxKey struct{}
+
+// TestLRUCache_GetOrComputeContext tests that the caller's context reaches compute and cancelling it stops the wait.
+func TestLRUCache_GetOrComputeContext(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cache.GetOrComputeContext(ctx, "k", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if cache.Contains("k") {
+		t.Fatal("Expected nothing to be cached for a failed load")
+	}
+
+	v, err := cache.GetOrComputeContext(context.Background(), "k", func(context.Context) (int, error) { return 1, nil })
+	if err != nil || v != 1 {
+		t.Fatalf("cache.GetOrComputeContext() = %d, %v; want 1, nil", v, err)
+	}
+}
+
+// TestLRUCache_GetOrComputeContextWaiter tests that a waiting caller returns its context error while the load goes on.
+func TestLRUCache_GetOrComputeContextWaiter(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := cache.GetOrComputeContext(context.Background(), "k", func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 42, nil
+		})
+		if err != nil || v != 42 {
+			t.Errorf("Leader got %d, %v; want 42, nil", v, err)
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.GetOrComputeContext(ctx, "k", func(context.Context) (int, error) {
+		t.Error("Expected the waiter not to start a second load")
+		return 0, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	close(release)
+	<-done
+	if v, ok := cache.Get("k"); !ok || v != 42 {
+		t.Fatalf("cache.Get(\"k\") = %d, %v; want 42, true", v, ok)
+	}
+}
+
+// TestLRUCache_GetOrComputeContextDetached tests that a detached load completes and is cached after its caller gives up.
+func TestLRUCache_GetOrComputeContextDetached(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.SetDetachedLoads(true)
+	release := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, 7))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := cache.GetOrComputeContext(ctx, "k", func(ctx context.Context) (int, error) {
+		<-release
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return ctx.Value(ctxKey{}).(int), nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	close(release)
+	v, err := cache.GetOrComputeContext(context.Background(), "k", func(context.Context) (int, error) {
+		return 0, errors.New("unexpected second load")
+	})
+	if err != nil || v != 7 {
+		t.Fatalf("cache.GetOrComputeContext() = %d, %v; want 7, nil", v, err)
+	}
+}
+
+// TestLRUCache_Compute tests increment-like updates and deletion by returning false.
+func TestLRUCache_Compute(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	inc := func(old int, _ bool) (int, bool) { return old + 1, true }
+
+	if v, ok := cache.Compute("n", inc); !ok || v != 1 {
+		t.Fatalf("cache.Compute(\"n\") = %d, %v; want %d, %v", v, ok, 1, true)
+	}
+	if v, ok := cache.Compute("n", inc); !ok || v != 2 {
+		t.Fatalf("cache.Compute(\"n\") = %d, %v; want %d, %v", v, ok, 2, true)
+	}
+
+	// Delete once the counter reaches 3.
+	dropAt3 := func(old int, existed bool) (int, bool) {
+		if !existed {
+			t.Fatal("Expected \"n\" to exist")
+		}
+		return old + 1, old+1 < 3
+	}
+	if v, ok := cache.Compute("n", dropAt3); ok || v != 0 {
+		t.Fatalf("cache.Compute(\"n\") = %d, %v; want %d, %v", v, ok, 0, false)
+	}
+	if _, ok := cache.Get("n"); ok {
+		t.Fatal("Expected \"n\" to be deleted")
+	}
+
+	// Declining to store a missing key leaves the cache untouched.
+	cache.Compute("missing", func(int, bool) (int, bool) { return 0, false })
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 0)
+	}
+}
+
+// TestLRUCache_ComputeConcurrent tests that concurrent Compute calls never lose an update.
+func TestLRUCache_ComputeConcurrent(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Compute("n", func(old int, _ bool) (int, bool) { return old + 1, true })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := cache.Get("n"); v != 800 {
+		t.Fatalf("cache.Get(\"n\") = %d; want %d", v, 800)
+	}
+}
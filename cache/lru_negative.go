@@ -0,0 +1,95 @@
+// lru_negative.go contains negative caching for LRUCache: remembering for a
+// short while that a key does not exist in the backing store, so that repeated
+// lookups of missing keys do not each reach the store.
+
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LookupResult is the outcome of LRUCache.Lookup.
+type LookupResult int
+
+const (
+	// LookupMiss means nothing is known about the key.
+	LookupMiss LookupResult = iota
+	// LookupHit means the key was found with a value.
+	LookupHit
+	// LookupNegative means the key was recently recorded as not existing.
+	LookupNegative
+)
+
+// String returns the name of the result.
+func (r LookupResult) String() string {
+	switch r {
+	case LookupHit:
+		return "LookupHit"
+	case LookupNegative:
+		return "LookupNegative"
+	default:
+		return "LookupMiss"
+	}
+}
+
+// SetNegativeCache enables negative caching: PutNegative records up to
+// capacity keys as not existing, each for ttl, in a separate LRU list that
+// never displaces regular entries. ttl is typically shorter than the TTL of
+// regular entries, so that a key created in the backing store becomes visible
+// soon. Calling it again discards the keys recorded so far. Both arguments
+// must be greater than zero.
+func (c *LRUCache[K, V]) SetNegativeCache(capacity int, ttl time.Duration) {
+	if capacity <= 0 || ttl <= 0 {
+		panic("cache: negative cache capacity and ttl must be greater than zero")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	neg := NewLRUCache[K, struct{}](capacity)
+	neg.ttl = ttl
+	neg.now = func() time.Time { return c.now() } // Only called with c.mu held.
+	c.negative = neg
+}
+
+// PutNegative records that key does not exist, replacing any value stored for
+// it. Until the negative TTL elapses, or key is stored or deleted, Lookup
+// reports LookupNegative for it; Get and the other accessors treat it as
+// absent. SetNegativeCache must have been called first.
+func (c *LRUCache[K, V]) PutNegative(key K) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.negative == nil {
+		panic("cache: negative caching is not enabled")
+	}
+	c.supersede(key)
+	if i, ok := c.dict[key]; ok {
+		c.remove(i)
+	}
+	c.negative.Put(key, struct{}{})
+}
+
+// Lookup is like Get, but distinguishes a key recorded by PutNegative from
+// one the cache knows nothing about. A negative result counts as a hit in
+// Stats, as it spares the caller a load just like a regular hit does.
+func (c *LRUCache[K, V]) Lookup(key K) (V, LookupResult) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var zero V
+	if i, ok := c.lookup(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		return c.entries[i].value, LookupHit
+	}
+	if c.negative != nil {
+		if _, ok := c.negative.Get(key); ok {
+			atomic.AddUint64(&c.hits, 1)
+			return zero, LookupNegative
+		}
+	}
+	atomic.AddUint64(&c.misses, 1)
+	return zero, LookupMiss
+}
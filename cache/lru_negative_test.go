@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_PutNegative tests that a negative entry is reported by Lookup, hidden from Get and expires after its TTL.
+func TestLRUCache_PutNegative(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+	cache.SetNegativeCache(2, time.Second)
+
+	cache.Put("a", 1)
+	cache.PutNegative("a")
+	cache.PutNegative("missing")
+	if _, r := cache.Lookup("a"); r != LookupNegative {
+		t.Errorf("cache.Lookup(a) = %v; want %v", r, LookupNegative)
+	}
+	if v, ok := cache.Get("missing"); ok || v != 0 {
+		t.Errorf("cache.Get(missing) = %d, %v; want 0, false", v, ok)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Errorf("cache.Len() = %d; want 0", n)
+	}
+	if _, r := cache.Lookup("other"); r != LookupMiss {
+		t.Errorf("cache.Lookup(other) = %v; want %v", r, LookupMiss)
+	}
+	if s := cache.Stats(); s.Hits != 1 || s.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %+v", s)
+	}
+
+	clk.Advance(time.Second)
+	if _, r := cache.Lookup("missing"); r != LookupMiss {
+		t.Errorf("cache.Lookup(missing) after the TTL = %v; want %v", r, LookupMiss)
+	}
+}
+
+// TestLRUCache_PutNegativeCleared tests that storing or deleting a key forgets its negative entry.
+func TestLRUCache_PutNegativeCleared(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.SetNegativeCache(2, time.Minute)
+
+	cache.PutNegative("a")
+	cache.Put("a", 1)
+	if v, r := cache.Lookup("a"); r != LookupHit || v != 1 {
+		t.Errorf("cache.Lookup(a) = %d, %v; want 1, %v", v, r, LookupHit)
+	}
+
+	cache.PutNegative("b")
+	if cache.Delete("b") {
+		t.Error("Expected Delete of a negative entry to report false")
+	}
+	if _, r := cache.Lookup("b"); r != LookupMiss {
+		t.Errorf("cache.Lookup(b) = %v; want %v", r, LookupMiss)
+	}
+
+	cache.PutNegative("c")
+	cache.Clear()
+	if _, r := cache.Lookup("c"); r != LookupMiss {
+		t.Errorf("cache.Lookup(c) after Clear = %v; want %v", r, LookupMiss)
+	}
+}
+
+// TestLRUCache_PutNegativeBounded tests that negative entries are bounded separately and never evict regular ones.
+func TestLRUCache_PutNegativeBounded(t *testing.T) {
+	cache := NewLRUCache[int, int](1)
+	cache.SetNegativeCache(2, time.Minute)
+	cache.Put(0, 0)
+
+	for i := 1; i <= 3; i++ {
+		cache.PutNegative(i)
+	}
+	if _, r := cache.Lookup(1); r != LookupMiss {
+		t.Errorf("Expected the oldest negative entry to be evicted, got %v", r)
+	}
+	for _, key := range []int{2, 3} {
+		if _, r := cache.Lookup(key); r != LookupNegative {
+			t.Errorf("cache.Lookup(%d) = %v; want %v", key, r, LookupNegative)
+		}
+	}
+	if _, ok := cache.Get(0); !ok {
+		t.Error("Expected the regular entry to be kept")
+	}
+}
+
+// TestLRUCache_PutNegativeDisabled tests that PutNegative panics unless negative caching is enabled.
+func TestLRUCache_PutNegativeDisabled(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected PutNegative to panic")
+		}
+	}()
+	NewLRUCache[int, int](1).PutNegative(1)
+}
+
+// TestLookupResult_String tests the names of the lookup results.
+func TestLookupResult_String(t *testing.T) {
+	for r, want := range map[LookupResult]string{LookupMiss: "LookupMiss", LookupHit: "LookupHit", LookupNegative: "LookupNegative"} {
+		if got := r.String(); got != want {
+			t.Errorf("%d.String() = %q; want %q", int(r), got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// lru_persist.go contains SaveTo and LoadFrom, which write an LRUCache's
+// entries to a stream and read them back, so that a restarted process can
+// start with a warm cache.
+
+package cache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+)
+
+// persistVersion is the version of the format written by SaveTo. LoadFrom
+// rejects streams of any other version.
+const persistVersion = 1
+
+// persistHeader starts a stream written by SaveTo.
+type persistHeader struct {
+	Version int // Format version; persistVersion.
+	Len     int // Number of entries that follow.
+}
+
+// persistEntry is a single entry in a stream written by SaveTo.
+type persistEntry[K comparable, V any] struct {
+	Key     K
+	Value   V
+	Tags    []string
+	TTL     time.Duration // Time left until the entry expires; zero if it never does.
+	Sliding time.Duration // TTL restarted by every hit; zero unless stored WithSliding.
+}
+
+// SaveTo writes the live entries of the cache to w in a versioned gob
+// encoding, together with their tags and the time each has left to live.
+// Entries are written from least to most recently used, so LoadFrom restores
+// their recency order. The entries are copied under the lock and encoded after
+// releasing it, so SaveTo does not block the cache while writing. Keys and
+// values must be encodable with encoding/gob; interface types held in them
+// must be registered with gob.Register.
+func (c *LRUCache[K, V]) SaveTo(w io.Writer) error {
+	c.mu.Lock()
+	now := c.now()
+	entries := make([]persistEntry[K, V], 0, len(c.dict))
+	for i := c.entries[sentinel].prev; i != sentinel; i = c.entries[i].prev {
+		e := &c.entries[i]
+		var ttl time.Duration
+		if !e.expires.IsZero() {
+			if ttl = e.expires.Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		entries = append(entries, persistEntry[K, V]{
+			Key:     e.key,
+			Value:   e.value,
+			Tags:    append([]string(nil), e.tags...),
+			TTL:     ttl,
+			Sliding: e.sliding,
+		})
+	}
+	c.mu.Unlock()
+
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(persistHeader{Version: persistVersion, Len: len(entries)}); err != nil {
+		return err
+	}
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFrom reads entries written by SaveTo from r and stores them in the cache
+// as if by Put, in their saved recency order, so that if the cache is too
+// small the least recently used entries are the ones evicted. Each entry keeps
+// its tags and expires after the time it had left when it was saved; entries
+// saved without an expiry do not get the cache's default TTL. Existing entries
+// with the same keys are replaced. The whole stream is decoded before the
+// cache is modified, so on error the cache is left unchanged.
+func (c *LRUCache[K, V]) LoadFrom(r io.Reader) error {
+	dec := gob.NewDecoder(r)
+	var hdr persistHeader
+	if err := dec.Decode(&hdr); err != nil {
+		return err
+	}
+	if hdr.Version != persistVersion {
+		return fmt.Errorf("cache: unsupported snapshot version %d", hdr.Version)
+	}
+	if hdr.Len < 0 {
+		return fmt.Errorf("cache: invalid snapshot length %d", hdr.Len)
+	}
+
+	var entries []persistEntry[K, V]
+	for i := 0; i < hdr.Len; i++ {
+		var pe persistEntry[K, V]
+		if err := dec.Decode(&pe); err != nil {
+			return err
+		}
+		entries = append(entries, pe)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	for _, pe := range entries {
+		e := c.set(pe.Key, pe.Value)
+		if e == nil {
+			continue // Outweighs the budget of this cache.
+		}
+		c.untag(e)
+		c.tag(e, pe.Tags)
+		e.expires = time.Time{}
+		if pe.TTL > 0 {
+			e.expires = now.Add(pe.TTL)
+		}
+		e.sliding = pe.Sliding
+		c.trim()
+	}
+	return nil
+}
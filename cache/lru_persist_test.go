@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_SaveToLoadFrom tests that a saved cache is restored with its values, recency order, tags and remaining TTLs.
+func TestLRUCache_SaveToLoadFrom(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	src := NewLRUCache[string, int](4)
+	src.SetClock(clk)
+	src.PutWithTTL("expired", 0, time.Second)
+	src.PutWithTTL("a", 1, time.Minute)
+	src.PutTagged("b", 2, "users")
+	src.PutWithOptions("c", 3, WithTTL(time.Minute), WithSliding())
+	clk.Advance(30 * time.Second)
+
+	var buf bytes.Buffer
+	if err := src.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	dst := NewLRUCache[string, int](4)
+	dst.SetClock(clk)
+	if err := dst.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if keys := dst.Keys(); !reflect.DeepEqual(keys, []string{"c", "b", "a"}) {
+		t.Fatalf("Expected keys [c b a], got %v", keys)
+	}
+
+	clk.Advance(31 * time.Second)
+	if _, ok := dst.Get("a"); ok {
+		t.Error("Expected a to expire after its remaining 30 seconds")
+	}
+	if v, ok := dst.Get("b"); !ok || v != 2 {
+		t.Errorf("dst.Get(b) = %d, %v; want 2, true", v, ok)
+	}
+	if _, ok := dst.Get("c"); ok {
+		t.Error("Expected c to expire after its remaining 30 seconds")
+	}
+	if n := dst.InvalidateTag("users"); n != 1 {
+		t.Errorf("dst.InvalidateTag(users) = %d; want 1", n)
+	}
+}
+
+// TestLRUCache_LoadFromSmaller tests that loading into a smaller cache keeps the most recently used entries.
+func TestLRUCache_LoadFromSmaller(t *testing.T) {
+	src := NewLRUCache[int, int](4)
+	for i := 1; i <= 4; i++ {
+		src.Put(i, i)
+	}
+	src.Get(1)
+
+	var buf bytes.Buffer
+	if err := src.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	dst := NewLRUCache[int, int](2)
+	if err := dst.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if keys := dst.Keys(); !reflect.DeepEqual(keys, []int{1, 4}) {
+		t.Errorf("Expected keys [1 4], got %v", keys)
+	}
+}
+
+// TestLRUCache_LoadFromInvalid tests that a truncated or foreign stream is rejected and leaves the cache unchanged.
+func TestLRUCache_LoadFromInvalid(t *testing.T) {
+	src := NewLRUCache[int, int](4)
+	src.Put(1, 1)
+	src.Put(2, 2)
+	var buf bytes.Buffer
+	if err := src.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	dst := NewLRUCache[int, int](4)
+	dst.Put(9, 9)
+	if err := dst.LoadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Error("Expected an error for a truncated stream")
+	}
+	if keys := dst.Keys(); !reflect.DeepEqual(keys, []int{9}) {
+		t.Errorf("Expected the cache to be unchanged, got keys %v", keys)
+	}
+
+	var other bytes.Buffer
+	if err := gob.NewEncoder(&other).Encode(persistHeader{Version: persistVersion + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.LoadFrom(&other); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
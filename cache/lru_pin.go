@@ -0,0 +1,48 @@
+// lru_pin.go contains pinning for LRUCache: exempting critical entries from
+// eviction while leaving them subject to their TTL and to explicit removal.
+
+package cache
+
+// Pin exempts key from eviction until Unpin is called: capacity and weight
+// evictions skip it and take the least recently used unpinned entry instead.
+// A pinned entry still expires with its TTL and is removed by Delete, Clear
+// and invalidation, which also unpins it. Pin reports whether key is pinned
+// afterwards; it fails if key is not present or if pinning it would pin every
+// slot of the cache, as at least one entry must stay evictable. If Resize
+// later shrinks the cache below its pinned entries, or pinned entries alone
+// exceed the weight budget, the cache holds more than its limit until entries
+// are unpinned. Evictions skip over pinned entries one by one, so pins are
+// meant for a few entries rather than a large share of the cache.
+func (c *LRUCache[K, V]) Pin(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.lookup(key)
+	if !ok {
+		return false
+	}
+	e := &c.entries[i]
+	if e.pinned {
+		return true
+	}
+	if c.pins+1 >= c.capacity {
+		return false
+	}
+	e.pinned = true
+	c.pins++
+	return true
+}
+
+// Unpin makes key evictable again and reports whether it was pinned.
+func (c *LRUCache[K, V]) Unpin(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.dict[key]
+	if !ok || !c.entries[i].pinned {
+		return false
+	}
+	c.entries[i].pinned = false
+	c.pins--
+	return true
+}
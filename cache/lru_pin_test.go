@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_Pin tests that eviction skips pinned entries and takes them again once unpinned.
+func TestLRUCache_Pin(t *testing.T) {
+	cache := NewLRUCache[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	if !cache.Pin("a") {
+		t.Fatal("Expected Pin(a) to succeed")
+	}
+	cache.Put("d", 4) // Evicts b, the least recently used unpinned entry.
+	if keys := cache.Keys(); !reflect.DeepEqual(keys, []string{"d", "c", "a"}) {
+		t.Fatalf("Expected keys [d c a], got %v", keys)
+	}
+
+	if !cache.Unpin("a") || cache.Unpin("a") {
+		t.Fatal("Expected Unpin to report true once")
+	}
+	cache.Put("e", 5)
+	if cache.Contains("a") {
+		t.Fatal("Expected a to be evicted once unpinned")
+	}
+}
+
+// TestLRUCache_PinLimit tests that pins cannot take every slot and fail for missing keys.
+func TestLRUCache_PinLimit(t *testing.T) {
+	cache := NewLRUCache[int, int](3)
+	for i := 0; i < 3; i++ {
+		cache.Put(i, i)
+	}
+
+	if cache.Pin(9) {
+		t.Error("Expected Pin of a missing key to fail")
+	}
+	if !cache.Pin(0) || !cache.Pin(1) || !cache.Pin(1) {
+		t.Fatal("Expected two pins to succeed")
+	}
+	if cache.Pin(2) {
+		t.Fatal("Expected pinning the last evictable slot to fail")
+	}
+
+	for i := 3; i < 6; i++ {
+		cache.Put(i, i)
+	}
+	if keys := cache.Keys(); !reflect.DeepEqual(keys, []int{5, 1, 0}) {
+		t.Fatalf("Expected keys [5 1 0], got %v", keys)
+	}
+}
+
+// TestLRUCache_PinRemoved tests that a pinned entry still expires and is deleted, releasing its pin.
+func TestLRUCache_PinRemoved(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](3)
+	cache.SetClock(clk)
+	cache.PutWithTTL("a", 1, time.Second)
+	cache.Put("b", 2)
+	cache.Pin("a")
+	cache.Pin("b")
+
+	clk.Advance(time.Second)
+	if cache.Contains("a") {
+		t.Error("Expected the pinned entry to expire")
+	}
+	if !cache.Delete("b") {
+		t.Error("Expected the pinned entry to be deleted")
+	}
+	if cache.pins != 0 {
+		t.Errorf("Expected no pins left, got %d", cache.pins)
+	}
+}
+
+// TestLRUCache_PinResize tests that shrinking below the pinned entries keeps them and exceeds the capacity.
+func TestLRUCache_PinResize(t *testing.T) {
+	cache := NewLRUCache[int, int](4)
+	for i := 0; i < 4; i++ {
+		cache.Put(i, i)
+	}
+	cache.Pin(0)
+	cache.Pin(1)
+	cache.Pin(2)
+
+	cache.Resize(2)
+	if keys := cache.Keys(); !reflect.DeepEqual(keys, []int{2, 1, 0}) {
+		t.Fatalf("Expected the pinned keys [2 1 0], got %v", keys)
+	}
+	cache.Put(4, 4)
+	if n := cache.Len(); n != 4 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 4)
+	}
+}
+
+// TestWeightedLRUCache_Pin tests that trimming to the weight budget skips pinned entries.
+func TestWeightedLRUCache_Pin(t *testing.T) {
+	cache := NewWeightedLRUCache[string, string](5, func(_ string, v string) int64 { return int64(len(v)) })
+	cache.Put("a", "xx")
+	cache.Put("b", "xx")
+	cache.Pin("a")
+
+	cache.Put("c", "xxx") // Over budget; b goes, pinned a stays.
+	if keys := cache.Keys(); !reflect.DeepEqual(keys, []string{"c", "a"}) {
+		t.Fatalf("Expected keys [c a], got %v", keys)
+	}
+	cache.Put("d", "xxxxxx") // Too heavy on its own: not stored, c and a stay.
+	if keys := cache.Keys(); !reflect.DeepEqual(keys, []string{"c", "a"}) {
+		t.Fatalf("Expected keys [c a], got %v", keys)
+	}
+}
@@ -0,0 +1,81 @@
+// lru_refresh.go contains refresh-ahead for LRUCache: reloading an entry in
+// the background once it has been read after a given age, so that hot keys
+// are kept up to date without any reader waiting on the loader.
+
+package cache
+
+import "time"
+
+// RefreshAfterWrite makes a read of an entry written at least d ago return the
+// current value immediately and reload it in the background with loader. At
+// most one reload per key runs at a time. Reads that promote an entry, such as
+// Get, GetMany and GetOrCompute hits, trigger reloads; Peek, Contains and
+// Range do not.
+//
+// A successful reload stores the new value as if by Put, keeping the entry's
+// TTL duration but restarting it, unless the entry was written, removed or
+// evicted while the reload ran, in which case the reloaded value is dropped.
+// A failed reload keeps the current value, so the next read retries. loader
+// runs on its own goroutine without the cache's lock held; Close waits for
+// reloads in progress. RefreshAfterWrite should be called before the cache is
+// used.
+func (c *LRUCache[K, V]) RefreshAfterWrite(d time.Duration, loader func(key K) (V, error)) {
+	if d <= 0 {
+		panic("cache: refresh interval must be greater than zero")
+	}
+	if loader == nil {
+		panic("cache: loader must not be nil")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refresh = d
+	c.reload = loader
+}
+
+// maybeReload starts a background reload of e if refresh-ahead is enabled, e
+// is due and no reload of it is running. The caller must hold c.mu.
+func (c *LRUCache[K, V]) maybeReload(e *entry[K, V]) {
+	if c.refresh <= 0 || c.now().Sub(e.written) < c.refresh {
+		return
+	}
+	if _, ok := c.inReload[e.key]; ok {
+		return
+	}
+	if c.inReload == nil {
+		c.inReload = make(map[K]struct{})
+	}
+	c.inReload[e.key] = struct{}{}
+
+	c.reloads.Add(1)
+	go c.reloadEntry(e.key, e.written, c.reload)
+}
+
+// reloadEntry loads key with loader and stores the result if the entry still
+// holds the value written at written.
+func (c *LRUCache[K, V]) reloadEntry(key K, written time.Time, loader func(key K) (V, error)) {
+	defer c.reloads.Done()
+	val, err := loader(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	delete(c.inReload, key)
+	if err != nil {
+		return
+	}
+	i, ok := c.dict[key]
+	if !ok || !c.entries[i].written.Equal(written) {
+		return
+	}
+	ttl := c.entries[i].expires.Sub(written)
+	e := c.set(key, val)
+	if e == nil {
+		return
+	}
+	if !e.expires.IsZero() {
+		e.expires = e.written.Add(ttl)
+	}
+	c.trim()
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestLRUCache_RefreshAfterWrite tests that reading an old entry returns it at once and reloads it exactly once in the background.
+func TestLRUCache_RefreshAfterWrite(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+	var calls int64
+	release := make(chan struct{})
+	cache.RefreshAfterWrite(time.Minute, func(string) (int, error) {
+		<-release
+		return int(atomic.AddInt64(&calls, 1)), nil
+	})
+
+	cache.Put("k", 0)
+	clk.Advance(30 * time.Second)
+	cache.Get("k")
+	cache.Close()
+	if n := atomic.LoadInt64(&calls); n != 0 {
+		t.Fatalf("Expected no reload before the refresh interval, got %d", n)
+	}
+
+	clk.Advance(30 * time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, ok := cache.Get("k"); !ok || v != 0 {
+				t.Errorf("cache.Get(\"k\") = %d, %v; want the current value 0, true", v, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	close(release)
+	cache.Close()
+
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Fatalf("Expected exactly 1 reload, got %d", n)
+	}
+	if v, ok := cache.Get("k"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"k\") after the reload = %d, %v; want 1, true", v, ok)
+	}
+}
+
+// TestLRUCache_RefreshAfterWriteTTL tests that a reload restarts the entry's TTL.
+func TestLRUCache_RefreshAfterWriteTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+	cache.RefreshAfterWrite(time.Minute, func(string) (int, error) { return 1, nil })
+
+	cache.PutWithTTL("k", 0, 2*time.Minute)
+	clk.Advance(90 * time.Second)
+	cache.Get("k")
+	cache.Close()
+
+	clk.Advance(time.Minute) // Past the original expiry, within the restarted one.
+	if v, ok := cache.Peek("k"); !ok || v != 1 {
+		t.Fatalf("cache.Peek(\"k\") = %d, %v; want 1, true", v, ok)
+	}
+	clk.Advance(time.Minute)
+	if cache.Contains("k") {
+		t.Fatal("Expected the reloaded entry to expire 2 minutes after the reload")
+	}
+}
+
+// TestLRUCache_RefreshAfterWriteConflicts tests that a failed reload keeps the value and a reload racing a write is dropped.
+func TestLRUCache_RefreshAfterWriteConflicts(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+	fail := true
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	cache.RefreshAfterWrite(time.Minute, func(string) (int, error) {
+		if fail {
+			return 0, errors.New("unavailable")
+		}
+		started <- struct{}{}
+		<-release
+		return 1, nil
+	})
+
+	cache.Put("k", 0)
+	clk.Advance(time.Minute)
+	cache.Get("k")
+	cache.Close()
+	if v, _ := cache.Get("k"); v != 0 {
+		t.Fatalf("Expected a failed reload to keep 0, got %d", v)
+	}
+	cache.Close()
+
+	fail = false
+	cache.Get("k")
+	<-started
+	cache.Put("k", 2) // Written while the reload runs.
+	close(release)
+	cache.Close()
+	if v, _ := cache.Peek("k"); v != 2 {
+		t.Fatalf("Expected the concurrent write 2 to win over the reload, got %d", v)
+	}
+}
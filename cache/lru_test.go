@@ -1,8 +1,12 @@
 package cache
 
 import (
+	"reflect"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
 )
 
 // TestLRUCache_PutGet tests basic put and get operations.
@@ -82,3 +86,821 @@ func TestLRUCache_Concurrency(t *testing.T) {
 		}
 	}
 }
+
+// TestLRUCache_InvalidateTag tests that invalidating a tag removes exactly the entries carrying it.
+func TestLRUCache_InvalidateTag(t *testing.T) {
+	cache := NewLRUCache[string, int](10)
+
+	cache.PutTagged("a", 1, "user:42")
+	cache.PutTagged("b", 2, "user:42", "product:7")
+	cache.PutTagged("c", 3, "product:7")
+	cache.Put("d", 4)
+
+	if n := cache.InvalidateTag("user:42"); n != 2 {
+		t.Fatalf("cache.InvalidateTag(\"user:42\") = %d; want %d", n, 2)
+	}
+	for _, key := range []string{"a", "b"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("Expected %q to be invalidated", key)
+		}
+	}
+	for _, key := range []string{"c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %q to remain cached", key)
+		}
+	}
+
+	// "b" was removed, so only "c" still carries "product:7".
+	if n := cache.InvalidateTag("product:7"); n != 1 {
+		t.Fatalf("cache.InvalidateTag(\"product:7\") = %d; want %d", n, 1)
+	}
+	if n := cache.InvalidateTag("missing"); n != 0 {
+		t.Fatalf("cache.InvalidateTag(\"missing\") = %d; want %d", n, 0)
+	}
+}
+
+// TestLRUCache_PutTaggedReplacesTags tests that re-tagging a key replaces its previous tags.
+func TestLRUCache_PutTaggedReplacesTags(t *testing.T) {
+	cache := NewLRUCache[string, int](10)
+
+	cache.PutTagged("a", 1, "old")
+	cache.PutTagged("a", 2, "new")
+
+	if n := cache.InvalidateTag("old"); n != 0 {
+		t.Fatalf("cache.InvalidateTag(\"old\") = %d; want %d", n, 0)
+	}
+	if n := cache.InvalidateTag("new"); n != 1 {
+		t.Fatalf("cache.InvalidateTag(\"new\") = %d; want %d", n, 1)
+	}
+}
+
+// TestLRUCache_TagIndexEviction tests that capacity eviction cleans up the tag index.
+func TestLRUCache_TagIndexEviction(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+
+	cache.PutTagged("a", 1, "t")
+	cache.PutTagged("b", 2, "t")
+	cache.Put("c", 3) // Evicts "a"
+
+	if keys := cache.tags["t"]; len(keys) != 1 {
+		t.Fatalf("Expected tag index to hold 1 key after eviction, got %d", len(keys))
+	}
+	cache.Put("d", 4) // Evicts "b"
+	if _, ok := cache.tags["t"]; ok {
+		t.Fatal("Expected empty tag to be removed from the index")
+	}
+
+	// A re-inserted key must not inherit stale tags from a pooled entry.
+	cache.Put("a", 1)
+	if n := cache.InvalidateTag("t"); n != 0 {
+		t.Fatalf("cache.InvalidateTag(\"t\") = %d; want %d", n, 0)
+	}
+}
+
+// TestLRUCache_WithLock tests that a multi-key update inside WithLock is observed atomically.
+func TestLRUCache_WithLock(t *testing.T) {
+	cache := NewLRUCache[string, int](10)
+	cache.Put("a", 100)
+	cache.Put("b", 0)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// Readers check that the total across both keys never changes.
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				cache.WithLock(func(ops CacheOps[string, int]) {
+					a, _ := ops.Get("a")
+					b, _ := ops.Get("b")
+					if a+b != 100 {
+						t.Errorf("Observed partial update: a=%d b=%d", a, b)
+					}
+				})
+			}
+		}()
+	}
+
+	// Move units from "a" to "b" one at a time.
+	for i := 0; i < 100; i++ {
+		cache.WithLock(func(ops CacheOps[string, int]) {
+			a, _ := ops.Get("a")
+			b, _ := ops.Get("b")
+			ops.Set("a", a-1)
+			ops.Set("b", b+1)
+		})
+	}
+	close(done)
+	wg.Wait()
+
+	if v, _ := cache.Get("b"); v != 100 {
+		t.Fatalf("cache.Get(\"b\") = %d; want %d", v, 100)
+	}
+
+	cache.WithLock(func(ops CacheOps[string, int]) {
+		if !ops.Delete("a") {
+			t.Error("Expected ops.Delete(\"a\") to report true")
+		}
+		if ops.Delete("a") {
+			t.Error("Expected second ops.Delete(\"a\") to report false")
+		}
+	})
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected \"a\" to be deleted")
+	}
+}
+
+// TestLRUCache_Stats tests that the stats snapshot reflects capacity and occupancy.
+func TestLRUCache_Stats(t *testing.T) {
+	cache := NewLRUCache[int, int](4)
+
+	if s := cache.Stats(); s != (CacheStats{Capacity: 4}) {
+		t.Fatalf("cache.Stats() = %+v; want empty cache with capacity 4", s)
+	}
+
+	cache.Put(1, 1)
+	cache.PutTagged(2, 2, "t")
+	cache.PutTagged(3, 3, "t")
+	if s := cache.Stats(); s.Length != 3 || s.LoadFactor != 0.75 {
+		t.Fatalf("cache.Stats() = %+v; want Length 3 and LoadFactor 0.75", s)
+	}
+
+	cache.Put(4, 4)
+	cache.Put(5, 5) // Evicts 1
+	if s := cache.Stats(); s.Length != 4 || s.LoadFactor != 1 {
+		t.Fatalf("cache.Stats() = %+v; want Length 4 and LoadFactor 1", s)
+	}
+
+	cache.InvalidateTag("t")
+	if s := cache.Stats(); s.Capacity != 4 || s.Length != 2 || s.LoadFactor != 0.5 {
+		t.Fatalf("cache.Stats() = %+v; want Capacity 4, Length 2 and LoadFactor 0.5", s)
+	}
+}
+
+// TestLRUCache_UpdateIf tests conditional updates with passing and failing predicates and a missing key.
+func TestLRUCache_UpdateIf(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("version", 3)
+
+	newer := func(v int) func(int) bool {
+		return func(old int) bool { return v > old }
+	}
+
+	if !cache.UpdateIf("version", newer(5), 5) {
+		t.Fatal("Expected update with a passing predicate to succeed")
+	}
+	if v, _ := cache.Get("version"); v != 5 {
+		t.Fatalf("cache.Get(\"version\") = %d; want %d", v, 5)
+	}
+
+	if cache.UpdateIf("version", newer(4), 4) {
+		t.Fatal("Expected update with a failing predicate to fail")
+	}
+	if v, _ := cache.Get("version"); v != 5 {
+		t.Fatalf("cache.Get(\"version\") = %d; want %d", v, 5)
+	}
+
+	if cache.UpdateIf("missing", func(int) bool { return true }, 1) {
+		t.Fatal("Expected update of a missing key to fail")
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatal("Expected UpdateIf not to insert a missing key")
+	}
+}
+
+// TestCompareAndSwap tests that the value is only replaced when it still equals the expected one.
+func TestCompareAndSwap(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("v", 1)
+
+	if CompareAndSwap(cache, "v", 2, 3) {
+		t.Fatal("Expected CompareAndSwap with a stale old value to fail")
+	}
+	if !CompareAndSwap(cache, "v", 1, 2) {
+		t.Fatal("Expected CompareAndSwap with the current value to succeed")
+	}
+	if v, _ := cache.Get("v"); v != 2 {
+		t.Errorf("cache.Get(\"v\") = %d; want 2", v)
+	}
+	if CompareAndSwap(cache, "missing", 0, 1) || cache.Contains("missing") {
+		t.Error("Expected CompareAndSwap of a missing key to fail without storing it")
+	}
+}
+
+// TestLRUCache_Swap tests that Swap returns the replaced value and promotes the key.
+func TestLRUCache_Swap(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if prev, ok := cache.Swap("a", 10); !ok || prev != 1 {
+		t.Fatalf("cache.Swap(\"a\", 10) = %d, %v; want %d, %v", prev, ok, 1, true)
+	}
+
+	// "a" was promoted by the swap, so inserting "c" evicts "b".
+	if prev, ok := cache.Swap("c", 3); ok || prev != 0 {
+		t.Fatalf("cache.Swap(\"c\", 3) = %d, %v; want %d, %v", prev, ok, 0, false)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected \"b\" to be evicted")
+	}
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Fatalf("cache.Get(\"c\") = %d, %v; want %d, %v", v, ok, 3, true)
+	}
+	if v, _ := cache.Get("a"); v != 10 {
+		t.Fatalf("cache.Get(\"a\") = %d; want %d", v, 10)
+	}
+}
+
+// TestLRUCache_SetOnEvict tests that the eviction callback receives capacity evictions but not explicit removals.
+func TestLRUCache_SetOnEvict(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	var got []KeyValue[string, int]
+	cache.SetOnEvict(func(key string, val int) {
+		got = append(got, KeyValue[string, int]{Key: key, Value: val})
+	})
+
+	cache.PutTagged("a", 1, "t")
+	cache.Put("b", 2)
+	cache.Put("c", 3) // Evicts "a".
+	cache.InvalidateTag("t")
+
+	if len(got) != 1 || got[0] != (KeyValue[string, int]{Key: "a", Value: 1}) {
+		t.Fatalf("Expected only the capacity eviction of \"a\" to be reported, got %v", got)
+	}
+}
+
+// TestLRUCache_SetOnEvictTTL tests that the eviction callback receives entries whose TTL elapsed.
+func TestLRUCache_SetOnEvictTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+	var got []string
+	cache.SetOnEvict(func(key string, _ int) { got = append(got, key) })
+
+	cache.PutWithTTL("a", 1, time.Second)
+	cache.PutWithTTL("b", 2, time.Second)
+	clk.Advance(time.Second)
+
+	cache.Get("a")
+	if len(got) != 1 || got[0] != "a" {
+		t.Fatalf("Expected the lazy expiry of \"a\" to be reported, got %v", got)
+	}
+	cache.RemoveExpired()
+	if len(got) != 2 || got[1] != "b" {
+		t.Fatalf("Expected the expiry of \"b\" to be reported, got %v", got)
+	}
+}
+
+// TestLRUCache_SetOnEvictReentrant tests that the eviction callback may call back into the cache.
+func TestLRUCache_SetOnEvictReentrant(t *testing.T) {
+	cache := NewLRUCache[int, int](2)
+	var evicted []int
+	cache.SetOnEvict(func(key, val int) {
+		evicted = append(evicted, key)
+		// Re-inserting evicts the current least recently used entry in turn,
+		// until the callback stops re-inserting.
+		if key == 1 {
+			cache.Put(key+100, val)
+		}
+		cache.Get(key)
+	})
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // Evicts 1, whose callback inserts 101 and evicts 2.
+
+	want := []int{1, 2}
+	if len(evicted) != len(want) || evicted[0] != want[0] || evicted[1] != want[1] {
+		t.Fatalf("Evicted %v; want %v", evicted, want)
+	}
+	for _, key := range []int{3, 101} {
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("Expected key %d to be cached", key)
+		}
+	}
+}
+
+// TestLRUCache_Delete tests that Delete removes a present key and reports a missing one.
+func TestLRUCache_Delete(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if !cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed")
+	}
+	if cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" twice to fail")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected \"a\" to be deleted")
+	}
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 1)
+	}
+
+	// The freed slot is reused, so both keys fit without evicting "b".
+	arena := len(cache.entries)
+	cache.Put("c", 3)
+	if len(cache.entries) != arena {
+		t.Fatalf("Expected the deleted slot to be reused, arena grew from %d to %d", arena, len(cache.entries))
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Fatal("Expected \"b\" to survive")
+	}
+}
+
+// TestLRUCache_Keys tests that Keys returns a copy ordered from most to least recently used.
+func TestLRUCache_Keys(t *testing.T) {
+	cache := NewLRUCache[string, int](3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+
+	keys := cache.Keys()
+	want := []string{"a", "c", "b"}
+	if len(keys) != len(want) {
+		t.Fatalf("cache.Keys() = %v; want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("cache.Keys() = %v; want %v", keys, want)
+		}
+	}
+
+	keys[0] = "z"
+	if got := cache.Keys(); got[0] != "a" {
+		t.Fatalf("Expected Keys to return a copy, got %v", got)
+	}
+	if keys := NewLRUCache[string, int](1).Keys(); len(keys) != 0 {
+		t.Fatalf("Expected no keys for an empty cache, got %v", keys)
+	}
+}
+
+// TestLRUCache_HitMissStats tests that hits, misses and evictions are counted and can be reset.
+func TestLRUCache_HitMissStats(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+
+	cache.Put("a", 1)
+	cache.PutWithTTL("b", 2, time.Second)
+	cache.Get("a")
+	cache.Get("missing")
+	clk.Advance(time.Second)
+	cache.Get("b")    // Expired, so a miss.
+	cache.Put("c", 3) // Fills the slot "b" freed.
+	cache.Put("d", 4) // Evicts "a".
+
+	s := cache.Stats()
+	if s.Hits != 1 || s.Misses != 2 || s.Evictions != 1 {
+		t.Fatalf("cache.Stats() = %+v; want 1 hit, 2 misses and 1 eviction", s)
+	}
+	if s.HitRatio != 1.0/3 {
+		t.Fatalf("Expected a hit ratio of 1/3, got %v", s.HitRatio)
+	}
+
+	cache.ResetStats()
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 0 || s.Evictions != 0 || s.HitRatio != 0 {
+		t.Fatalf("Expected zeroed counters after ResetStats, got %+v", s)
+	}
+}
+
+// TestLRUCache_HitMissStatsConcurrent tests that concurrent Gets are all counted.
+func TestLRUCache_HitMissStatsConcurrent(t *testing.T) {
+	cache := NewLRUCache[int, int](10)
+	cache.Put(0, 0)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Get(i % 2) // Key 0 hits, key 1 misses.
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s := cache.Stats(); s.Hits != 400 || s.Misses != 400 {
+		t.Fatalf("cache.Stats() = %+v; want 400 hits and 400 misses", s)
+	}
+}
+
+// TestLRUCache_Resize tests that shrinking evicts least recently used entries and growing keeps them all.
+func TestLRUCache_Resize(t *testing.T) {
+	cache := NewLRUCache[int, int](5)
+	var evicted []int
+	cache.SetOnEvict(func(key, _ int) { evicted = append(evicted, key) })
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	cache.Get(0)
+
+	cache.Resize(2)
+	want := []int{1, 2, 3}
+	if len(evicted) != len(want) {
+		t.Fatalf("Evicted %v; want %v", evicted, want)
+	}
+	for i := range want {
+		if evicted[i] != want[i] {
+			t.Fatalf("Evicted %v; want %v", evicted, want)
+		}
+	}
+	if keys := cache.Keys(); len(keys) != 2 || keys[0] != 0 || keys[1] != 4 {
+		t.Fatalf("cache.Keys() = %v; want [0 4]", keys)
+	}
+	if s := cache.Stats(); s.Capacity != 2 || s.Length != 2 {
+		t.Fatalf("cache.Stats() = %+v; want Capacity 2 and Length 2", s)
+	}
+
+	cache.Resize(4)
+	for i := 5; i < 7; i++ {
+		cache.Put(i, i)
+	}
+	if n := cache.Len(); n != 4 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 4)
+	}
+	if len(evicted) != len(want) {
+		t.Fatalf("Expected growing not to evict, evicted %v", evicted)
+	}
+}
+
+// TestLRUCache_ResizeLowersBatch tests that shrinking below the eviction batch lowers the batch to the new capacity.
+func TestLRUCache_ResizeLowersBatch(t *testing.T) {
+	cache := NewLRUCache[int, int](4)
+	cache.SetEvictionBatch(4)
+	cache.Resize(2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // Evicts both entries at once, not more than the cache holds.
+
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 1)
+	}
+	if _, ok := cache.Get(3); !ok {
+		t.Fatal("Expected key 3 to be cached")
+	}
+}
+
+// TestLRUCache_ResizeInvalid tests that Resize panics for a non-positive capacity.
+func TestLRUCache_ResizeInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected Resize(0) to panic")
+		}
+	}()
+	NewLRUCache[int, int](1).Resize(0)
+}
+
+// TestLRUCache_TryPut tests that TryPut fills the cache, then rejects new keys but still updates existing ones.
+func TestLRUCache_TryPut(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+
+	if !cache.TryPut("a", 1) || !cache.TryPut("b", 2) {
+		t.Fatal("Expected TryPut to succeed while there is room")
+	}
+	if cache.TryPut("c", 3) {
+		t.Fatal("Expected TryPut of a new key to fail when full")
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("Expected the rejected key not to be cached")
+	}
+	if !cache.TryPut("a", 10) {
+		t.Fatal("Expected TryPut to update an existing key when full")
+	}
+	if v, _ := cache.Get("a"); v != 10 {
+		t.Fatalf("cache.Get(\"a\") = %d; want %d", v, 10)
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Fatal("Expected TryPut not to evict \"b\"")
+	}
+}
+
+// TestLRUCache_AvgEvictedLifetime tests that the average lifetime of evicted entries is tracked from insertion.
+func TestLRUCache_AvgEvictedLifetime(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](2)
+	cache.SetClock(clk)
+
+	if d := cache.AvgEvictedLifetime(); d != 0 {
+		t.Fatalf("Expected zero before any eviction, got %v", d)
+	}
+
+	cache.Put("a", 1)
+	clk.Advance(10 * time.Second)
+	cache.Put("b", 2)
+	cache.Put("a", 3) // Updating does not restart the lifetime, but promotes "a".
+	clk.Advance(20 * time.Second)
+	cache.Put("c", 3) // Evicts "b", aged 20s.
+	clk.Advance(10 * time.Second)
+	cache.Put("d", 4) // Evicts "a", aged 40s.
+
+	if d := cache.AvgEvictedLifetime(); d != 30*time.Second {
+		t.Fatalf("cache.AvgEvictedLifetime() = %v; want %v", d, 30*time.Second)
+	}
+
+	cache.ResetStats()
+	if d := cache.AvgEvictedLifetime(); d != 0 {
+		t.Fatalf("Expected zero after ResetStats, got %v", d)
+	}
+}
+
+// TestLRUCache_SetEvictionBatch tests that a full cache evicts a batch at once and stays within the documented band.
+func TestLRUCache_SetEvictionBatch(t *testing.T) {
+	cache := NewLRUCache[int, int](10)
+	cache.SetEvictionBatch(3)
+
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	cache.Put(10, 10) // Evicts 0, 1 and 2.
+	if n := cache.Len(); n != 8 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 8)
+	}
+	for _, key := range []int{0, 1, 2} {
+		if _, ok := cache.Get(key); ok {
+			t.Fatalf("Expected key %d to be evicted", key)
+		}
+	}
+
+	for i := 11; i < 100; i++ {
+		cache.Put(i, i)
+		if n := cache.Len(); n < 8 || n > 10 {
+			t.Fatalf("Expected length within [8, 10], got %d", n)
+		}
+	}
+	if s := cache.Stats(); s.Evictions != 90 {
+		t.Fatalf("Expected 90 evictions, got %d", s.Evictions)
+	}
+}
+
+// TestLRUCache_SetEvictionBatchInvalid tests that an out-of-range batch size panics.
+func TestLRUCache_SetEvictionBatchInvalid(t *testing.T) {
+	for _, n := range []int{0, 11} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected SetEvictionBatch(%d) to panic", n)
+				}
+			}()
+			NewLRUCache[int, int](10).SetEvictionBatch(n)
+		}()
+	}
+}
+
+// TestLRUCache_Contains tests that Contains reports presence without promoting the key.
+func TestLRUCache_Contains(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if !cache.Contains("a") || cache.Contains("missing") {
+		t.Fatal("Expected Contains to report only present keys")
+	}
+	cache.Put("c", 3) // "a" is still least recently used and is evicted.
+	if cache.Contains("a") {
+		t.Error("Expected Contains not to promote \"a\"")
+	}
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Errorf("Expected Contains not to count lookups, got %+v", s)
+	}
+}
+
+// TestLRUCache_Peek tests that Peek returns values without changing the eviction order.
+func TestLRUCache_Peek(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if v, ok := cache.Peek("a"); !ok || v != 1 {
+		t.Fatalf("cache.Peek(\"a\") = %v, %v; want 1, true", v, ok)
+	}
+	if _, ok := cache.Peek("missing"); ok {
+		t.Fatal("Expected Peek to miss an absent key")
+	}
+
+	cache.Put("c", 3)
+	if _, ok := cache.Peek("a"); ok {
+		t.Error("Expected \"a\" to be evicted as it was not promoted")
+	}
+	if keys := cache.Keys(); keys[0] != "c" || keys[1] != "b" {
+		t.Errorf("Expected keys [c b], got %v", keys)
+	}
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Errorf("Expected Peek not to count lookups, got %+v", s)
+	}
+}
+
+// TestLRUCache_Clear tests that Clear empties the cache and that it is fully usable afterwards.
+func TestLRUCache_Clear(t *testing.T) {
+	cache := NewLRUCache[int, int](3)
+	var evicted int
+	cache.SetOnEvict(func(int, int) { evicted++ })
+	for i := 0; i < 3; i++ {
+		cache.PutTagged(i, i, "t")
+	}
+
+	cache.Clear()
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected an empty cache, got %d entries", n)
+	}
+	if evicted != 3 {
+		t.Errorf("Expected Clear to report 3 evictions, got %d", evicted)
+	}
+	if n := cache.InvalidateTag("t"); n != 0 {
+		t.Errorf("Expected the tag index to be cleared, removed %d", n)
+	}
+
+	for i := 10; i < 14; i++ {
+		cache.Put(i, i)
+	}
+	if keys := cache.Keys(); len(keys) != 3 || keys[0] != 13 || keys[2] != 11 {
+		t.Errorf("Expected keys [13 12 11] after refilling, got %v", keys)
+	}
+	if evicted != 4 {
+		t.Errorf("Expected 1 more eviction after refilling, got %d", evicted-3)
+	}
+}
+
+// TestLRUCache_Range tests that Range walks live entries in recency order and may call back into the cache.
+func TestLRUCache_Range(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+	cache.Put("a", 1)
+	cache.PutWithTTL("expired", 0, time.Second)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	clk.Advance(time.Second)
+
+	var keys []string
+	cache.Range(func(key string, val int) bool {
+		keys = append(keys, key)
+		cache.Put(key+"!", val) // Must not deadlock.
+		return len(keys) < 3
+	})
+	if len(keys) != 3 || keys[0] != "c" || keys[1] != "b" || keys[2] != "a" {
+		t.Fatalf("Expected [c b a], got %v", keys)
+	}
+
+	n := 0
+	cache.Range(func(string, int) bool { n++; return false })
+	if n != 1 {
+		t.Errorf("Expected Range to stop after fn returned false, got %d calls", n)
+	}
+}
+
+// TestLRUCache_InvalidateBefore tests that entries written before the cutoff are removed while newer ones survive.
+func TestLRUCache_InvalidateBefore(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](10)
+	cache.SetClock(clk)
+
+	cache.Put("old1", 1)
+	cache.Put("old2", 2)
+	cache.Put("rewritten", 3)
+	clk.Advance(time.Minute)
+	cutoff := clk.Now()
+	cache.Put("new", 4)
+	cache.Put("rewritten", 5) // Rewriting refreshes the timestamp.
+	cache.Get("old1")         // Reading does not.
+
+	if n := cache.InvalidateBefore(cutoff); n != 2 {
+		t.Fatalf("cache.InvalidateBefore() = %d; want %d", n, 2)
+	}
+	for _, key := range []string{"old1", "old2"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("Expected %q to be invalidated", key)
+		}
+	}
+	for _, key := range []string{"new", "rewritten"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %q to survive", key)
+		}
+	}
+	if n := cache.InvalidateBefore(cutoff); n != 0 {
+		t.Fatalf("Second cache.InvalidateBefore() = %d; want %d", n, 0)
+	}
+}
+
+// TestLRUCache_OnEvictBatch tests that entries removed by one operation are reported in a single batch.
+func TestLRUCache_OnEvictBatch(t *testing.T) {
+	cache := NewLRUCache[string, int](10)
+
+	var batches [][]KeyValue[string, int]
+	cache.OnEvictBatch(func(entries []KeyValue[string, int]) {
+		batches = append(batches, entries)
+	})
+
+	cache.PutTagged("a", 1, "t")
+	cache.PutTagged("b", 2, "t")
+	cache.PutTagged("c", 3, "t")
+	cache.Put("d", 4)
+	if len(batches) != 0 {
+		t.Fatalf("Expected no callbacks without evictions, got %d", len(batches))
+	}
+
+	cache.InvalidateTag("t")
+	if len(batches) != 1 {
+		t.Fatalf("Expected 1 batch, got %d", len(batches))
+	}
+	got := make(map[string]int)
+	for _, kv := range batches[0] {
+		got[kv.Key] = kv.Value
+	}
+	if len(got) != 3 || got["a"] != 1 || got["b"] != 2 || got["c"] != 3 {
+		t.Fatalf("Unexpected batch contents: %v", batches[0])
+	}
+}
+
+// TestLRUCache_OnEvictBatchResize tests that a large shrink reports all evicted entries in a single batch, least recently used first.
+func TestLRUCache_OnEvictBatchResize(t *testing.T) {
+	cache := NewLRUCache[int, int](10)
+
+	var batches [][]KeyValue[int, int]
+	cache.OnEvictBatch(func(entries []KeyValue[int, int]) {
+		batches = append(batches, entries)
+	})
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	cache.Get(0)
+
+	cache.Resize(4)
+	if len(batches) != 1 {
+		t.Fatalf("Expected 1 batch, got %d", len(batches))
+	}
+	want := []KeyValue[int, int]{{1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 5}, {6, 6}}
+	if !reflect.DeepEqual(batches[0], want) {
+		t.Errorf("Expected batch %v, got %v", want, batches[0])
+	}
+}
+
+// TestLRUCache_OnEvictBatchClear tests that Clear reports all entries in a single batch, least recently used first.
+func TestLRUCache_OnEvictBatchClear(t *testing.T) {
+	cache := NewLRUCache[string, int](10)
+
+	var batches [][]KeyValue[string, int]
+	cache.OnEvictBatch(func(entries []KeyValue[string, int]) {
+		batches = append(batches, entries)
+	})
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+
+	cache.Clear()
+	if len(batches) != 1 {
+		t.Fatalf("Expected 1 batch, got %d", len(batches))
+	}
+	want := []KeyValue[string, int]{{"b", 2}, {"c", 3}, {"a", 1}}
+	if !reflect.DeepEqual(batches[0], want) {
+		t.Errorf("Expected batch %v, got %v", want, batches[0])
+	}
+
+	cache.Clear()
+	if len(batches) != 1 {
+		t.Errorf("Expected clearing an empty cache not to fire a batch, got %d", len(batches))
+	}
+}
+
+// TestWeightedLRUCache_OnEvictBatch tests that a weight eviction of several entries fires one batch.
+func TestWeightedLRUCache_OnEvictBatch(t *testing.T) {
+	cache := NewWeightedLRUCache(10, func(_ string, v int) int64 { return int64(v) })
+
+	calls := 0
+	var evicted []KeyValue[string, int]
+	cache.OnEvictBatch(func(entries []KeyValue[string, int]) {
+		calls++
+		evicted = append(evicted, entries...)
+	})
+
+	cache.Put("a", 3)
+	cache.Put("b", 3)
+	cache.Put("c", 3)
+	cache.Put("big", 9) // Evicts "a", "b" and "c" in one operation.
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 batch callback, got %d", calls)
+	}
+	want := []KeyValue[string, int]{{"a", 3}, {"b", 3}, {"c", 3}}
+	if len(evicted) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, evicted)
+	}
+	for i := range want {
+		if evicted[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, evicted)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// lru_ttl.go contains the time-to-live support of LRUCache: per-entry TTLs
+// checked lazily on access and an optional background janitor that reclaims
+// expired entries that are never read again.
+
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+	"github.com/edast/go-utils/internal/random"
+)
+
+// NewLRUCacheWithTTL creates an LRUCache whose entries expire ttl after they
+// were last stored by Put or any other method that does not take its own TTL.
+// It also starts a janitor that removes expired entries every ttl; Close stops
+// it.
+func NewLRUCacheWithTTL[K comparable, V any](capacity int, ttl time.Duration) *LRUCache[K, V] {
+	if ttl <= 0 {
+		panic("cache: ttl must be greater than zero")
+	}
+
+	c := NewLRUCache[K, V](capacity)
+	c.ttl = ttl
+	c.StartJanitor(ttl)
+	return c
+}
+
+// SetTTLJitter spreads the default TTL of each entry uniformly over
+// [ttl-ttl*frac, ttl+ttl*frac], so that entries written together, such as by
+// a warm-up, do not all expire together. frac is clamped to at most 1; 0
+// disables jitter. TTLs passed explicitly, such as to PutWithTTL, are not
+// jittered.
+func (c *LRUCache[K, V]) SetTTLJitter(frac float64) {
+	if frac < 0 {
+		panic("cache: jitter must not be negative")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.jitter = frac
+}
+
+// PutWithTTL adds or updates a key-value pair like Put, but the entry expires
+// after ttl. An expired entry is reported as missing and removed when it is
+// next accessed, or earlier by the janitor if one is running.
+func (c *LRUCache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e := c.set(key, val)
+	if e == nil {
+		return
+	}
+	e.expires = c.now().Add(ttl)
+	e.sliding = 0
+	c.trim()
+}
+
+// PutOption configures how PutWithOptions stores an entry.
+type PutOption func(*putOptions)
+
+// putOptions collects the PutOptions passed to PutWithOptions.
+type putOptions struct {
+	ttl     time.Duration
+	sliding bool
+}
+
+// WithTTL makes the entry expire after d instead of the cache's default TTL.
+func WithTTL(d time.Duration) PutOption {
+	return func(o *putOptions) { o.ttl = d }
+}
+
+// WithSliding restarts the entry's TTL on every hit, so that it only expires
+// once it has gone unused for a whole TTL, as suits session caches. It has no
+// effect on an entry without a TTL.
+func WithSliding() PutOption {
+	return func(o *putOptions) { o.sliding = true }
+}
+
+// PutWithOptions adds or updates a key-value pair like Put, configured by
+// opts. Without WithTTL the entry gets the cache's default TTL, if any. Hits
+// that restart a sliding TTL are those of Get, GetOrCompute and CacheOps.Get;
+// Peek and Contains leave it alone.
+func (c *LRUCache[K, V]) PutWithOptions(key K, val V, opts ...PutOption) {
+	var o putOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	e := c.set(key, val)
+	if e == nil {
+		return
+	}
+	c.resetExpiry(e)
+	if o.ttl > 0 {
+		e.expires = c.now().Add(o.ttl)
+	}
+	if o.sliding && !e.expires.IsZero() {
+		e.sliding = e.expires.Sub(c.now())
+	}
+	c.trim()
+}
+
+// GetMultiRefresh looks up keys like Get in a single locked pass and returns
+// the live entries found. Each found entry's TTL is reset to expire ttl from
+// now, including entries that had no TTL before. Missing and expired keys are
+// absent from the result.
+func (c *LRUCache[K, V]) GetMultiRefresh(keys []K, ttl time.Duration) map[K]V {
+	c.mu.Lock()
+	defer c.unlock()
+
+	found := make(map[K]V, len(keys))
+	expires := c.now().Add(ttl)
+	for _, key := range keys {
+		i, ok := c.lookup(key)
+		if !ok {
+			atomic.AddUint64(&c.misses, 1)
+			continue
+		}
+		atomic.AddUint64(&c.hits, 1)
+		c.touch(i)
+		e := &c.entries[i]
+		e.expires = expires
+		found[key] = e.value
+	}
+	return found
+}
+
+// SetClock makes the cache read the time from clk, for TTLs and entry
+// timestamps, and drive its janitor with timers from clk. Tests pass a
+// clock.Fake to control expiry without sleeping. A janitor already running,
+// such as the one started by NewLRUCacheWithTTL, is restarted on clk. It must
+// be called before the cache is used.
+func (c *LRUCache[K, V]) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	c.clk = clk
+	c.now = clk.Now
+	running, interval := c.janitor != nil, c.interval
+	c.mu.Unlock()
+
+	if running {
+		c.StartJanitor(interval)
+	}
+}
+
+// StartJanitor starts a background goroutine that removes expired entries
+// every interval. A janitor that is already running is stopped first.
+func (c *LRUCache[K, V]) StartJanitor(interval time.Duration) {
+	if interval <= 0 {
+		panic("cache: janitor interval must be greater than zero")
+	}
+	c.StopJanitor()
+
+	stop := make(chan struct{})
+	c.mu.Lock()
+	c.janitor = stop
+	c.interval = interval
+	clk := c.clk
+	c.mu.Unlock()
+	if clk == nil {
+		clk = clock.Real()
+	}
+
+	c.sweeping.Add(1)
+	go func() {
+		defer c.sweeping.Done()
+
+		for {
+			timer := clk.NewTimer(interval)
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-timer.C():
+				c.RemoveExpired()
+			}
+		}
+	}()
+}
+
+// StopJanitor stops the background janitor, if one is running, and waits for
+// it to exit.
+func (c *LRUCache[K, V]) StopJanitor() {
+	c.mu.Lock()
+	stop := c.janitor
+	c.janitor = nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		c.sweeping.Wait()
+	}
+}
+
+// Close stops the background janitor, if one is running, like StopJanitor,
+// and waits for refresh-ahead reloads in progress to finish. The cache remains
+// usable afterwards, with expired entries removed lazily on access.
+func (c *LRUCache[K, V]) Close() {
+	c.StopJanitor()
+	c.reloads.Wait()
+}
+
+// RemoveExpired removes all entries whose TTL has elapsed and returns the
+// number of entries removed. It scans the whole cache.
+func (c *LRUCache[K, V]) RemoveExpired() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	n := 0
+	for i := c.entries[sentinel].next; i != sentinel; {
+		next := c.entries[i].next // expire clears the entry's links.
+		if exp := c.entries[i].expires; !exp.IsZero() && !now.Before(exp) {
+			atomic.AddUint64(&c.expired, 1)
+			c.expire(i)
+			n++
+		}
+		i = next
+	}
+	return n
+}
+
+// defaultExpiry returns the expiry of an entry stored without its own TTL:
+// the default TTL from now, or zero if the cache has none. The caller must
+// hold c.mu.
+func (c *LRUCache[K, V]) defaultExpiry() time.Time {
+	if c.ttl == 0 {
+		return time.Time{}
+	}
+	if c.jitter == 0 {
+		return c.now().Add(c.ttl)
+	}
+	src := c.rand
+	if src == nil {
+		src = random.Default
+	}
+	return c.now().Add(random.Jitter(src, c.ttl, c.jitter))
+}
+
+// resetExpiry gives e the cache's default TTL from now, replacing any TTL it
+// had, sliding or not. The caller must hold c.mu.
+func (c *LRUCache[K, V]) resetExpiry(e *entry[K, V]) {
+	e.expires = c.defaultExpiry()
+	e.sliding = 0
+}
@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+	"github.com/edast/go-utils/internal/random"
+)
+
+// TestLRUCache_PutWithTTL tests that an entry is a miss once its TTL has elapsed.
+func TestLRUCache_PutWithTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, string](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("short", "a", time.Second)
+	cache.PutWithTTL("long", "b", time.Minute)
+	cache.Put("forever", "c")
+
+	if v, ok := cache.Get("short"); !ok || v != "a" {
+		t.Fatalf("cache.Get(\"short\") = %v, %v; want %v, %v", v, ok, "a", true)
+	}
+
+	clk.Advance(time.Second)
+	if _, ok := cache.Get("short"); ok {
+		t.Fatal("Expected \"short\" to have expired")
+	}
+	if n := cache.Stats().Length; n != 2 {
+		t.Fatalf("Expected the expired entry to be removed on access, got length %d", n)
+	}
+
+	clk.Advance(time.Hour)
+	if _, ok := cache.Get("long"); ok {
+		t.Fatal("Expected \"long\" to have expired")
+	}
+	if v, ok := cache.Get("forever"); !ok || v != "c" {
+		t.Fatalf("cache.Get(\"forever\") = %v, %v; want %v, %v", v, ok, "c", true)
+	}
+}
+
+// TestLRUCache_PutClearsTTL tests that a plain Put over a TTL entry makes it permanent.
+func TestLRUCache_PutClearsTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("k", 1, time.Second)
+	cache.Put("k", 2)
+	clk.Advance(time.Minute)
+
+	if v, ok := cache.Get("k"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"k\") = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestCacheOps_SetResetsTTL tests that CacheOps.Set replaces an entry's TTL with the cache's default, like Put.
+func TestCacheOps_SetResetsTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("k", 1, time.Second)
+	cache.WithLock(func(ops CacheOps[string, int]) { ops.Set("k", 2) })
+	clk.Advance(time.Minute)
+
+	if v, ok := cache.Get("k"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"k\") = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestLRUCache_PutTaggedResetsTTL tests that PutTagged replaces an entry's TTL with the cache's default, like Put.
+func TestLRUCache_PutTaggedResetsTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("k", 1, time.Second)
+	cache.PutTagged("k", 2, "t")
+	clk.Advance(time.Minute)
+
+	if v, ok := cache.Get("k"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"k\") = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestLRUCache_ExpiredSlotReuse tests that the slot of an expired entry is recycled.
+func TestLRUCache_ExpiredSlotReuse(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[int, int](2)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL(1, 1, time.Second)
+	cache.Put(2, 2)
+	clk.Advance(time.Second)
+
+	if n := cache.RemoveExpired(); n != 1 {
+		t.Fatalf("cache.RemoveExpired() = %d; want %d", n, 1)
+	}
+	arena := len(cache.entries)
+	cache.Put(3, 3)
+	if len(cache.entries) != arena {
+		t.Fatalf("Expected the expired slot to be reused, arena grew from %d to %d", arena, len(cache.entries))
+	}
+	if _, ok := cache.Get(2); !ok {
+		t.Fatal("Expected key 2 to survive, as the expired entry freed room")
+	}
+}
+
+// TestLRUCache_Janitor tests that the janitor reclaims expired entries that are never read.
+func TestLRUCache_Janitor(t *testing.T) {
+	cache := NewLRUCache[int, int](100)
+	for i := 0; i < 10; i++ {
+		cache.PutWithTTL(i, i, 10*time.Millisecond)
+	}
+	cache.Put(100, 100)
+
+	cache.StartJanitor(5 * time.Millisecond)
+	defer cache.StopJanitor()
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats().Length > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := cache.Stats().Length; n != 1 {
+		t.Fatalf("Expected the janitor to leave 1 entry, got %d", n)
+	}
+
+	cache.StopJanitor()
+	cache.StopJanitor() // Stopping twice is a no-op.
+}
+
+// TestLRUCache_GetMultiRefresh tests that found keys get an extended TTL and missing ones are absent.
+func TestLRUCache_GetMultiRefresh(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("a", 1, time.Second)
+	cache.PutWithTTL("b", 2, time.Second)
+	cache.PutWithTTL("stale", 3, time.Millisecond)
+	clk.Advance(500 * time.Millisecond)
+
+	found := cache.GetMultiRefresh([]string{"a", "missing", "stale"}, time.Minute)
+	if len(found) != 1 || found["a"] != 1 {
+		t.Fatalf("cache.GetMultiRefresh() = %v; want map[a:1]", found)
+	}
+
+	clk.Advance(time.Second)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("Expected \"a\" to live on after its refresh, got %v, %v", v, ok)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected the unrefreshed \"b\" to have expired")
+	}
+}
+
+// TestNewLRUCacheWithTTL tests that entries stored without their own TTL expire after the default one.
+func TestNewLRUCacheWithTTL(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCacheWithTTL[string, int](4, time.Minute)
+	defer cache.Close()
+	cache.SetClock(clk)
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.PutWithTTL("c", 3, time.Hour)
+	clk.Advance(30 * time.Second)
+	cache.Put("b", 20) // Restarts the default TTL.
+	clk.Advance(45 * time.Second)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected \"a\" to have expired after the default TTL")
+	}
+	if v, ok := cache.Get("b"); !ok || v != 20 {
+		t.Errorf("Expected the updated \"b\" to be live, got %v, %v", v, ok)
+	}
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Errorf("Expected \"c\" to keep its own TTL, got %v, %v", v, ok)
+	}
+}
+
+// TestLRUCache_Close tests that the janitor started by NewLRUCacheWithTTL reclaims entries until Close.
+func TestLRUCache_Close(t *testing.T) {
+	cache := NewLRUCacheWithTTL[int, int](10, 10*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected the janitor to remove all entries, got %d", n)
+	}
+
+	cache.Close()
+	cache.Close() // Closing twice is a no-op.
+}
+
+// TestLRUCache_ExpirationStats tests that TTL removals are counted as expirations rather than evictions.
+func TestLRUCache_ExpirationStats(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[int, int](2)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL(1, 1, time.Second)
+	cache.PutWithTTL(2, 2, time.Second)
+	cache.Put(3, 3) // Evicts 1.
+	clk.Advance(time.Second)
+
+	cache.Get(2)          // Expired on access.
+	cache.RemoveExpired() // Nothing left to expire.
+	cache.PutWithTTL(4, 4, time.Second)
+	clk.Advance(time.Second)
+	cache.RemoveExpired() // Expires 4.
+
+	s := cache.Stats()
+	if s.Evictions != 1 || s.Expirations != 2 || s.Length != 1 {
+		t.Fatalf("Expected 1 eviction, 2 expirations and 1 entry, got %+v", s)
+	}
+	cache.ResetStats()
+	if s := cache.Stats(); s.Expirations != 0 {
+		t.Errorf("Expected ResetStats to zero expirations, got %d", s.Expirations)
+	}
+}
+
+// TestLRUCache_PutWithOptions tests per-entry TTLs and sliding expiration set through options.
+func TestLRUCache_PutWithOptions(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithOptions("session", 1, WithTTL(time.Minute), WithSliding())
+	cache.PutWithOptions("fixed", 2, WithTTL(time.Minute))
+	cache.PutWithOptions("forever", 3)
+
+	for i := 0; i < 3; i++ {
+		clk.Advance(40 * time.Second)
+		if _, ok := cache.Get("session"); !ok {
+			t.Fatalf("Expected the sliding entry to live on while used, expired after %d hits", i)
+		}
+	}
+	if _, ok := cache.Get("fixed"); ok {
+		t.Error("Expected the fixed TTL entry to expire despite being read")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("Expected an entry without TTL never to expire")
+	}
+
+	cache.Peek("session") // Does not restart the TTL.
+	clk.Advance(time.Minute)
+	if _, ok := cache.Get("session"); ok {
+		t.Error("Expected the sliding entry to expire after a whole unused TTL")
+	}
+}
+
+// TestLRUCache_PutClearsSliding tests that a plain Put replaces a sliding TTL with the default one.
+func TestLRUCache_PutClearsSliding(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithOptions("k", 1, WithTTL(time.Second), WithSliding())
+	cache.Put("k", 2)
+	clk.Advance(time.Hour)
+	if v, ok := cache.Get("k"); !ok || v != 2 {
+		t.Fatalf("Expected Put to clear the TTL, got %v, %v", v, ok)
+	}
+}
+
+// TestLRUCache_SetClock tests that TTLs and the janitor follow an injected fake clock.
+func TestLRUCache_SetClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCache[string, int](4)
+	cache.SetClock(clk)
+
+	cache.PutWithTTL("a", 1, time.Minute)
+	cache.Put("b", 2)
+	cache.StartJanitor(time.Minute)
+	defer cache.StopJanitor()
+
+	for clk.Timers() == 0 { // Wait for the janitor to arm its timer.
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(time.Minute)
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("Expected the janitor to remove the expired entry, got %d entries", n)
+	}
+}
+
+// TestNewLRUCacheWithTTL_SetClock tests that the janitor started by the constructor follows a clock set afterwards.
+func TestNewLRUCacheWithTTL_SetClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewLRUCacheWithTTL[string, int](4, time.Minute)
+	defer cache.Close()
+	cache.SetClock(clk)
+
+	cache.Put("a", 1)
+	for clk.Timers() == 0 { // Wait for the restarted janitor to arm its timer.
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(time.Minute)
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected the janitor to remove the expired entry, got %d entries", n)
+	}
+}
+
+// TestLRUCache_TTLJitter tests that jittered default TTLs stay in range, vary and repeat for the same seed.
+func TestLRUCache_TTLJitter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	expiries := func(seed int64) []time.Time {
+		cache := NewLRUCacheWithTTL[int, int](100, time.Minute)
+		defer cache.Close()
+		cache.SetClock(clock.NewFake(start))
+		cache.SetTTLJitter(0.1)
+		cache.rand = random.New(seed)
+
+		var out []time.Time
+		for i := 0; i < 50; i++ {
+			cache.Put(i, i)
+			out = append(out, cache.entries[cache.dict[i]].expires)
+		}
+		cache.PutWithTTL(-1, -1, time.Minute)
+		if exp := cache.entries[cache.dict[-1]].expires; !exp.Equal(start.Add(time.Minute)) {
+			t.Errorf("Expected an explicit TTL not to be jittered, got expiry %v", exp.Sub(start))
+		}
+		return out
+	}
+
+	a, b := expiries(42), expiries(42)
+	varied := false
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			t.Fatalf("Expected identical expiries for the same seed, got %v and %v", a[i], b[i])
+		}
+		if d := a[i].Sub(start); d < 54*time.Second || d > 66*time.Second {
+			t.Fatalf("Expected a TTL within 10%% of a minute, got %v", d)
+		}
+		varied = varied || !a[i].Equal(a[0])
+	}
+	if !varied {
+		t.Fatal("Expected jitter to vary the TTLs")
+	}
+}
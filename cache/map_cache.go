@@ -0,0 +1,81 @@
+// map_cache.go contains the MapCache and NopCache types, trivial Cache
+// implementations without an eviction policy, intended for tests and for
+// disabling caching through configuration.
+
+package cache
+
+import "sync"
+
+// MapCache is an unbounded Cache backed by a map. It never evicts, so it is
+// only suitable when the set of keys is known to be small, such as in tests.
+// MapCache is safe for concurrent use by multiple goroutines.
+type MapCache[K comparable, V any] struct {
+	dict map[K]V    // Stored entries.
+	mu   sync.Mutex // Mutex to protect concurrent access to the cache.
+}
+
+// NewMapCache creates a new, empty MapCache.
+func NewMapCache[K comparable, V any]() *MapCache[K, V] {
+	return &MapCache[K, V]{dict: make(map[K]V)}
+}
+
+// Get retrieves the value associated with the given key.
+func (c *MapCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.dict[key]
+	return val, ok
+}
+
+// Put adds or updates a key-value pair.
+func (c *MapCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dict[key] = val
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *MapCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.dict[key]
+	delete(c.dict, key)
+	return ok
+}
+
+// Len returns the number of entries in the cache.
+func (c *MapCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.dict)
+}
+
+// Close does nothing, as a MapCache holds no background resources.
+func (c *MapCache[K, V]) Close() {}
+
+// NopCache is a Cache that stores nothing: every Get misses. It can stand in
+// for a real cache to disable caching without changing call sites. Its zero
+// value is ready to use.
+type NopCache[K comparable, V any] struct{}
+
+// Get always reports that key was not found.
+func (NopCache[K, V]) Get(key K) (V, bool) {
+	var zero V
+	return zero, false
+}
+
+// Put discards the key-value pair.
+func (NopCache[K, V]) Put(key K, val V) {}
+
+// Delete always reports that key was not present.
+func (NopCache[K, V]) Delete(key K) bool { return false }
+
+// Len always returns zero.
+func (NopCache[K, V]) Len() int { return 0 }
+
+// Close does nothing.
+func (NopCache[K, V]) Close() {}
@@ -0,0 +1,39 @@
+package cache
+
+import "testing"
+
+// TestMapCache tests that MapCache stores every entry without evicting.
+func TestMapCache(t *testing.T) {
+	var c Cache[int, int] = NewMapCache[int, int]()
+	defer c.Close()
+
+	for i := 0; i < 1000; i++ {
+		c.Put(i, i*2)
+	}
+	if n := c.Len(); n != 1000 {
+		t.Fatalf("Expected length 1000, got %d", n)
+	}
+	if v, ok := c.Get(0); !ok || v != 0 {
+		t.Fatalf("c.Get(0) = %v, %v; want %v, %v", v, ok, 0, true)
+	}
+	if !c.Delete(0) || c.Delete(0) {
+		t.Fatal("Expected deleting 0 to succeed once")
+	}
+	if _, ok := c.Get(0); ok {
+		t.Error("Expected 0 to be gone after Delete")
+	}
+}
+
+// TestNopCache tests that NopCache never retains anything.
+func TestNopCache(t *testing.T) {
+	var c Cache[string, int] = NopCache[string, int]{}
+	defer c.Close()
+
+	c.Put("a", 1)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected Get to miss")
+	}
+	if c.Delete("a") || c.Len() != 0 {
+		t.Error("Expected NopCache to report nothing stored")
+	}
+}
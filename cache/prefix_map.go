@@ -0,0 +1,167 @@
+// prefix_map.go contains the implementation of the PrefixMap type, a map with
+// string keys backed by a trie so that all keys sharing a prefix can be
+// enumerated without scanning the whole map.
+
+package cache
+
+import (
+	"sort"
+	"sync"
+)
+
+// prefixNode is a trie node. The path from the root to a node spells the key
+// it stores, if any.
+type prefixNode[V any] struct {
+	children map[byte]*prefixNode[V] // Child nodes by next key byte; nil if there are none.
+	value    V                       // Value stored at this node, if set.
+	set      bool                    // Whether a key ends at this node.
+}
+
+// PrefixMap is a map from string keys to values that supports enumerating all
+// keys with a given prefix in time proportional to the prefix length plus the
+// number of matching keys. It is safe for concurrent use by multiple
+// goroutines.
+type PrefixMap[V any] struct {
+	root prefixNode[V] // Node for the empty key.
+	len  int           // Number of keys stored.
+	mu   sync.RWMutex  // Mutex to protect the trie.
+}
+
+// NewPrefixMap creates a new, empty PrefixMap.
+func NewPrefixMap[V any]() *PrefixMap[V] {
+	return &PrefixMap[V]{}
+}
+
+// Len returns the number of keys in the map.
+func (m *PrefixMap[V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.len
+}
+
+// Get returns the value stored for key and whether it was present.
+func (m *PrefixMap[V]) Get(key string) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if n := m.find(key); n != nil && n.set {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores val for key, replacing any previous value.
+func (m *PrefixMap[V]) Put(key string, val V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := &m.root
+	for i := 0; i < len(key); i++ {
+		child, ok := n.children[key[i]]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[byte]*prefixNode[V])
+			}
+			child = &prefixNode[V]{}
+			n.children[key[i]] = child
+		}
+		n = child
+	}
+	if !n.set {
+		m.len++
+	}
+	n.value = val
+	n.set = true
+}
+
+// Delete removes key from the map and reports whether it was present. Nodes
+// left without keys beneath them are pruned.
+func (m *PrefixMap[V]) Delete(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := make([]*prefixNode[V], 0, len(key)+1)
+	n := &m.root
+	path = append(path, n)
+	for i := 0; i < len(key); i++ {
+		if n = n.children[key[i]]; n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if !n.set {
+		return false
+	}
+
+	var zero V
+	n.value = zero
+	n.set = false
+	m.len--
+
+	// Walk back up, unlinking nodes that no longer lead to any key.
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i].set || len(path[i].children) > 0 {
+			break
+		}
+		delete(path[i-1].children, key[i-1])
+	}
+	return true
+}
+
+// WithPrefix returns an iterator over every key that starts with prefix and
+// its value, in lexicographic byte order. The iterator has the type of
+// iter.Seq2[string, V], so on Go 1.23 and later it can be ranged over
+// directly; it may also be called with a yield function that returns false
+// to stop early. Each iteration takes a snapshot of the matching entries
+// first, so yield runs without the lock held and may modify the map; such
+// changes are not reflected in the ongoing iteration.
+func (m *PrefixMap[V]) WithPrefix(prefix string) func(yield func(key string, val V) bool) {
+	return func(yield func(key string, val V) bool) {
+		m.mu.RLock()
+		var entries []KeyValue[string, V]
+		if n := m.find(prefix); n != nil {
+			buf := []byte(prefix)
+			n.collect(&buf, &entries)
+		}
+		m.mu.RUnlock()
+
+		for _, e := range entries {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// find returns the node for key, or nil if no stored key starts with key. The
+// caller must hold m.mu.
+func (m *PrefixMap[V]) find(key string) *prefixNode[V] {
+	n := &m.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+	}
+	return n
+}
+
+// collect appends the entries at and below n to entries in lexicographic
+// order. buf holds the key spelled by the path to n and is restored before
+// collect returns.
+func (n *prefixNode[V]) collect(buf *[]byte, entries *[]KeyValue[string, V]) {
+	if n.set {
+		*entries = append(*entries, KeyValue[string, V]{Key: string(*buf), Value: n.value})
+	}
+
+	next := make([]byte, 0, len(n.children))
+	for b := range n.children {
+		next = append(next, b)
+	}
+	sort.Slice(next, func(i, j int) bool { return next[i] < next[j] })
+
+	for _, b := range next {
+		*buf = append(*buf, b)
+		n.children[b].collect(buf, entries)
+		*buf = (*buf)[:len(*buf)-1]
+	}
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// prefixKeys returns the keys enumerated by WithPrefix for prefix.
+func prefixKeys[V any](m *PrefixMap[V], prefix string) []string {
+	var keys []string
+	m.WithPrefix(prefix)(func(key string, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// TestPrefixMap_PutGetDelete tests basic map operations.
+func TestPrefixMap_PutGetDelete(t *testing.T) {
+	m := NewPrefixMap[int]()
+	m.Put("a/b", 1)
+	m.Put("a", 2)
+	m.Put("a/b", 3)
+
+	if v, ok := m.Get("a/b"); !ok || v != 3 {
+		t.Fatalf("m.Get(\"a/b\") = %d, %v; want %d, %v", v, ok, 3, true)
+	}
+	if _, ok := m.Get("a/"); ok {
+		t.Fatal("Expected an inner node without a key to be missing")
+	}
+	if n := m.Len(); n != 2 {
+		t.Fatalf("m.Len() = %d; want %d", n, 2)
+	}
+
+	if !m.Delete("a/b") || m.Delete("a/b") || m.Delete("a/x") {
+		t.Fatal("Expected deleting \"a/b\" to succeed exactly once")
+	}
+	if _, ok := m.root.children['a'].children['/']; ok {
+		t.Fatal("Expected the nodes below \"a\" to be pruned")
+	}
+	if v, ok := m.Get("a"); !ok || v != 2 {
+		t.Fatalf("m.Get(\"a\") = %d, %v; want %d, %v", v, ok, 2, true)
+	}
+}
+
+// TestPrefixMap_WithPrefix tests that exactly the keys under a prefix are enumerated, in order.
+func TestPrefixMap_WithPrefix(t *testing.T) {
+	m := NewPrefixMap[int]()
+	for i, key := range []string{"a/b/2", "a/b/1", "a/c", "a/b", "b/a", "a/bc"} {
+		m.Put(key, i)
+	}
+
+	if got, want := prefixKeys(m, "a/b/"), []string{"a/b/1", "a/b/2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WithPrefix(\"a/b/\") = %v; want %v", got, want)
+	}
+	if got, want := prefixKeys(m, "a/b"), []string{"a/b", "a/b/1", "a/b/2", "a/bc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WithPrefix(\"a/b\") = %v; want %v", got, want)
+	}
+	if got := prefixKeys(m, ""); len(got) != 6 {
+		t.Errorf("Expected the empty prefix to match all 6 keys, got %v", got)
+	}
+	if got := prefixKeys(m, "z"); len(got) != 0 {
+		t.Errorf("Expected no keys under \"z\", got %v", got)
+	}
+
+	n := 0
+	m.WithPrefix("a")(func(string, int) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("Expected enumeration to stop when yield returns false, got %d calls", n)
+	}
+
+	seq := m.WithPrefix("z")
+	m.Put("z1", 1)
+	keys := 0
+	seq(func(string, int) bool { keys++; return true })
+	if keys != 1 {
+		t.Errorf("Expected an iterator to reflect keys added before it runs, got %d keys", keys)
+	}
+}
+
+// TestPrefixMap_Concurrent tests concurrent modification and enumeration for data races.
+func TestPrefixMap_Concurrent(t *testing.T) {
+	m := NewPrefixMap[int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			prefix := "g" + strconv.Itoa(g) + "/"
+			for i := 0; i < 100; i++ {
+				m.Put(prefix+strconv.Itoa(i), i)
+				m.WithPrefix(prefix)(func(key string, _ int) bool {
+					if i%10 == 0 {
+						m.Delete(key) // Modifying the map from yield must not deadlock.
+					}
+					return true
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// The last clearing pass ran at i == 90, leaving keys 91 to 99.
+	for g := 0; g < 8; g++ {
+		if keys := prefixKeys(m, "g"+strconv.Itoa(g)+"/"); len(keys) != 9 {
+			t.Errorf("Expected 9 keys for goroutine %d, got %d", g, len(keys))
+		}
+	}
+}
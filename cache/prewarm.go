@@ -0,0 +1,73 @@
+// prewarm.go contains Prewarm, which populates an LRUCache from a loader
+// function with bounded parallelism, typically at startup.
+
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Prewarm loads keys into the cache using load, running at most parallelism
+// loads at a time. onProgress, if not nil, is called after each load completes
+// with the number of completed loads and the total number of keys; calls are
+// serialized and done increases monotonically.
+//
+// Successfully loaded entries are stored as they complete and are kept even if
+// ctx is cancelled partway. Prewarm returns the keys whose load failed and,
+// if ctx was cancelled before all keys were attempted, ctx.Err(). Keys that
+// were never attempted because of the cancellation are not reported as failed.
+func (c *LRUCache[K, V]) Prewarm(ctx context.Context, keys []K, parallelism int,
+	load func(ctx context.Context, key K) (V, error), onProgress func(done, total int)) ([]K, error) {
+	if parallelism <= 0 {
+		panic("cache: parallelism must be greater than zero")
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex // Protects failed and done, and serializes onProgress.
+		failed []K
+		done   int
+	)
+	sem := make(chan struct{}, parallelism)
+
+	var err error
+	for _, key := range keys {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case sem <- struct{}{}:
+		}
+		if err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(key K) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			val, loadErr := load(ctx, key)
+			if loadErr == nil {
+				c.Put(key, val)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if loadErr != nil {
+				failed = append(failed, key)
+			}
+			done++
+			if onProgress != nil {
+				onProgress(done, len(keys))
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	return failed, err
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLRUCache_Prewarm tests that all keys are loaded, failures are reported and progress is monotonic.
+func TestLRUCache_Prewarm(t *testing.T) {
+	cache := NewLRUCache[int, int](100)
+	keys := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	var progress []int
+	failed, err := cache.Prewarm(context.Background(), keys, 3,
+		func(_ context.Context, key int) (int, error) {
+			if key%5 == 0 {
+				return 0, errors.New("load failed")
+			}
+			return key * 10, nil
+		},
+		func(done, total int) {
+			if total != len(keys) {
+				t.Errorf("Expected total %d, got %d", len(keys), total)
+			}
+			progress = append(progress, done)
+		})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(failed) != 2 {
+		t.Fatalf("Expected 2 failed keys, got %v", failed)
+	}
+	for i, done := range progress {
+		if done != i+1 {
+			t.Fatalf("Expected monotonic progress, got %v", progress)
+		}
+	}
+	if len(progress) != len(keys) {
+		t.Fatalf("Expected %d progress calls, got %d", len(keys), len(progress))
+	}
+	for _, key := range keys {
+		v, ok := cache.Get(key)
+		if key%5 == 0 {
+			if ok {
+				t.Errorf("Expected failed key %d not to be cached", key)
+			}
+		} else if !ok || v != key*10 {
+			t.Errorf("cache.Get(%d) = %v, %v; want %v, %v", key, v, ok, key*10, true)
+		}
+	}
+}
+
+// TestLRUCache_PrewarmParallelism tests that no more than parallelism loads run at once.
+func TestLRUCache_PrewarmParallelism(t *testing.T) {
+	cache := NewLRUCache[int, int](100)
+	keys := make([]int, 20)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	var running, peak int64
+	_, err := cache.Prewarm(context.Background(), keys, 4,
+		func(_ context.Context, key int) (int, error) {
+			n := atomic.AddInt64(&running, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			return key, nil
+		}, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if peak > 4 {
+		t.Fatalf("Expected at most 4 concurrent loads, observed %d", peak)
+	}
+}
+
+// TestLRUCache_PrewarmCancel tests that cancellation returns ctx.Err() and keeps completed entries.
+func TestLRUCache_PrewarmCancel(t *testing.T) {
+	cache := NewLRUCache[int, int](100)
+	keys := make([]int, 50)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var once sync.Once
+	_, err := cache.Prewarm(ctx, keys, 1,
+		func(_ context.Context, key int) (int, error) {
+			if key == 4 {
+				once.Do(cancel)
+			}
+			return key, nil
+		}, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	for key := 0; key <= 4; key++ {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected loaded key %d to be retained", key)
+		}
+	}
+	if _, ok := cache.Get(len(keys) - 1); ok {
+		t.Error("Expected keys after cancellation not to be loaded")
+	}
+}
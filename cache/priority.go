@@ -0,0 +1,155 @@
+// priority.go contains the implementation of the PriorityCache type, a cache
+// that evicts by an explicit per-entry priority first and by recency only to
+// break ties between entries of equal priority.
+
+package cache
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// priorityEntry holds a key-value pair, its priority and when it was last used.
+type priorityEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	priority int
+	used     uint64 // Value of the cache's clock when the entry was last used.
+	index    int    // Position in the eviction heap.
+}
+
+// priorityHeap orders entries so that the next eviction victim, the lowest
+// priority entry and among those the least recently used, is at the root.
+type priorityHeap[K comparable, V any] []*priorityEntry[K, V]
+
+func (h priorityHeap[K, V]) Len() int { return len(h) }
+
+func (h priorityHeap[K, V]) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].used < h[j].used
+}
+
+func (h priorityHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *priorityHeap[K, V]) Push(x any) {
+	e := x.(*priorityEntry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *priorityHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil // Release the reference for the garbage collector.
+	*h = old[:n-1]
+	return e
+}
+
+// PriorityCache is a fixed-capacity cache that, when full, evicts the entry
+// with the lowest priority, choosing the least recently used one among entries
+// of equal priority. A low-priority entry is therefore evicted before a
+// high-priority one no matter how recently it was used. Get, Put and eviction
+// are O(log n). PriorityCache is safe for concurrent use.
+type PriorityCache[K comparable, V any] struct {
+	capacity int                        // Maximum number of items the cache can hold.
+	heap     priorityHeap[K, V]         // Eviction order, victim at the root.
+	dict     map[K]*priorityEntry[K, V] // Map for quick access to entries.
+	clock    uint64                     // Logical clock ordering uses by recency.
+	mu       sync.Mutex                 // Mutex to protect concurrent access to the cache.
+}
+
+// NewPriorityCache creates a new PriorityCache with the given capacity.
+func NewPriorityCache[K comparable, V any](capacity int) *PriorityCache[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+
+	return &PriorityCache[K, V]{
+		capacity: capacity,
+		heap:     make(priorityHeap[K, V], 0, capacity),
+		dict:     make(map[K]*priorityEntry[K, V], capacity),
+	}
+}
+
+// Get retrieves the value associated with the given key and marks it as most
+// recently used among entries of its priority.
+func (c *PriorityCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok {
+		c.touch(e)
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds or updates a key-value pair with priority zero.
+func (c *PriorityCache[K, V]) Put(key K, val V) {
+	c.PutWithPriority(key, val, 0)
+}
+
+// PutWithPriority adds or updates a key-value pair with the given priority,
+// replacing the priority of an existing key. If the cache is full, the lowest
+// priority, least recently used entry is evicted first.
+func (c *PriorityCache[K, V]) PutWithPriority(key K, val V, priority int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok {
+		e.value = val
+		e.priority = priority
+		c.touch(e)
+		return
+	}
+
+	if len(c.heap) >= c.capacity {
+		victim := heap.Pop(&c.heap).(*priorityEntry[K, V])
+		delete(c.dict, victim.key)
+	}
+
+	c.clock++
+	e := &priorityEntry[K, V]{key: key, value: val, priority: priority, used: c.clock}
+	heap.Push(&c.heap, e)
+	c.dict[key] = e
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *PriorityCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	if ok {
+		heap.Remove(&c.heap, e.index)
+		delete(c.dict, key)
+	}
+	return ok
+}
+
+// Close does nothing, as a PriorityCache holds no background resources. It
+// exists to satisfy Cache.
+func (c *PriorityCache[K, V]) Close() {}
+
+// Len returns the number of entries in the cache.
+func (c *PriorityCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.heap)
+}
+
+// touch marks e as most recently used and restores the heap order.
+func (c *PriorityCache[K, V]) touch(e *priorityEntry[K, V]) {
+	c.clock++
+	e.used = c.clock
+	heap.Fix(&c.heap, e.index)
+}
@@ -0,0 +1,94 @@
+package cache
+
+import "testing"
+
+// TestPriorityCache_PutGet tests basic put and get operations.
+func TestPriorityCache_PutGet(t *testing.T) {
+	cache := NewPriorityCache[string, int](2)
+	cache.Put("a", 1)
+	cache.PutWithPriority("b", 2, 5)
+
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"a\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"b\") = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("Expected a missing key to be reported as such")
+	}
+}
+
+// TestPriorityCache_EvictsLowPriorityFirst tests that a recent low-priority entry is evicted before an old high-priority one.
+func TestPriorityCache_EvictsLowPriorityFirst(t *testing.T) {
+	cache := NewPriorityCache[string, int](2)
+	cache.PutWithPriority("old", 1, 10)
+	cache.PutWithPriority("recent", 2, 1)
+	cache.Get("recent")
+
+	cache.PutWithPriority("new", 3, 10)
+
+	if _, ok := cache.Get("recent"); ok {
+		t.Fatal("Expected the low-priority entry to be evicted")
+	}
+	if _, ok := cache.Get("old"); !ok {
+		t.Fatal("Expected the high-priority entry to survive")
+	}
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 2)
+	}
+}
+
+// TestPriorityCache_RecencyBreaksTies tests that among equal priorities the least recently used entry is evicted.
+func TestPriorityCache_RecencyBreaksTies(t *testing.T) {
+	cache := NewPriorityCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+
+	cache.Put("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected the recently used entry to survive")
+	}
+}
+
+// TestPriorityCache_UpdatePriority tests that re-putting a key replaces its priority.
+func TestPriorityCache_UpdatePriority(t *testing.T) {
+	cache := NewPriorityCache[string, int](2)
+	cache.PutWithPriority("a", 1, 10)
+	cache.PutWithPriority("b", 2, 5)
+	cache.PutWithPriority("a", 1, 0) // Demote "a" below "b".
+
+	cache.PutWithPriority("c", 3, 5)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected the demoted entry to be evicted")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Fatal("Expected \"b\" to survive")
+	}
+}
+
+// TestPriorityCache_Delete tests that deleting an entry keeps the eviction order intact.
+func TestPriorityCache_Delete(t *testing.T) {
+	cache := NewPriorityCache[string, int](3)
+	cache.PutWithPriority("low", 1, 0)
+	cache.PutWithPriority("mid", 2, 5)
+	cache.PutWithPriority("high", 3, 9)
+
+	if !cache.Delete("low") || cache.Delete("low") {
+		t.Fatal("Expected deleting \"low\" to succeed once")
+	}
+	cache.Put("new", 4)
+	cache.PutWithPriority("top", 5, 10) // Full again: evicts "new", the lowest priority.
+	if _, ok := cache.Get("new"); ok {
+		t.Error("Expected \"new\" to be evicted first")
+	}
+	if n := cache.Len(); n != 3 {
+		t.Errorf("Expected length 3, got %d", n)
+	}
+}
@@ -0,0 +1,148 @@
+// recording.go contains the RecordingCache wrapper and Replay, which record
+// the access pattern of a cache and play it back against another cache, for
+// example to compare capacities against real traffic.
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Operations recorded in a trace.
+const (
+	opGet    = "get"
+	opPut    = "put"
+	opDelete = "delete"
+)
+
+// traceRecord is a single line of a recorded trace.
+type traceRecord[K comparable] struct {
+	Op  string `json:"op"`
+	Key K      `json:"key"`
+}
+
+// RecordingCache wraps a Cache and writes every Get, Put and Delete to an
+// io.Writer as a trace of newline-delimited JSON records holding the operation
+// and key.
+// Values are not recorded. RecordingCache is safe for concurrent use if the
+// wrapped cache is.
+type RecordingCache[K comparable, V any] struct {
+	cache Cache[K, V]   // The wrapped cache.
+	enc   *json.Encoder // Writes trace records.
+	err   error         // First error encountered while writing the trace.
+	mu    sync.Mutex    // Mutex to serialize trace writes.
+}
+
+// NewRecordingCache creates a RecordingCache that forwards to cache and writes
+// its trace to w.
+func NewRecordingCache[K comparable, V any](cache Cache[K, V], w io.Writer) *RecordingCache[K, V] {
+	return &RecordingCache[K, V]{
+		cache: cache,
+		enc:   json.NewEncoder(w),
+	}
+}
+
+// Get retrieves the value from the wrapped cache and records the access.
+func (c *RecordingCache[K, V]) Get(key K) (V, bool) {
+	c.record(opGet, key)
+	return c.cache.Get(key)
+}
+
+// Put stores the value in the wrapped cache and records the access.
+func (c *RecordingCache[K, V]) Put(key K, val V) {
+	c.record(opPut, key)
+	c.cache.Put(key, val)
+}
+
+// Delete removes key from the wrapped cache and records the access.
+func (c *RecordingCache[K, V]) Delete(key K) bool {
+	c.record(opDelete, key)
+	return c.cache.Delete(key)
+}
+
+// Len returns the number of entries in the wrapped cache. It is not recorded.
+func (c *RecordingCache[K, V]) Len() int {
+	return c.cache.Len()
+}
+
+// Close closes the wrapped cache.
+func (c *RecordingCache[K, V]) Close() {
+	c.cache.Close()
+}
+
+// Err returns the first error encountered while writing the trace, if any.
+// Recording stops after the first error.
+func (c *RecordingCache[K, V]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// record appends an operation to the trace.
+func (c *RecordingCache[K, V]) record(op string, key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = c.enc.Encode(traceRecord[K]{Op: op, Key: key})
+	}
+}
+
+// ReplayResult summarizes the outcome of replaying a trace.
+type ReplayResult struct {
+	Gets    int // Number of Get operations replayed.
+	Puts    int // Number of Put operations replayed.
+	Deletes int // Number of Delete operations replayed.
+	Hits    int // Number of Gets that found their key.
+	Misses  int // Number of Gets that did not find their key.
+}
+
+// HitRatio returns the fraction of Gets that were hits, or 0 if there were none.
+func (r ReplayResult) HitRatio() float64 {
+	if r.Gets == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(r.Gets)
+}
+
+// Replay reads a trace written by a RecordingCache from r and applies it to
+// target. Since values are not recorded, Puts store the zero value of V.
+// Replay returns the hit and miss counts observed on target, together with an
+// error if the trace could not be decoded.
+func Replay[K comparable, V any](r io.Reader, target Cache[K, V]) (ReplayResult, error) {
+	var res ReplayResult
+	var zero V
+
+	dec := json.NewDecoder(r)
+	for {
+		var rec traceRecord[K]
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return res, err
+		}
+
+		switch rec.Op {
+		case opGet:
+			res.Gets++
+			if _, ok := target.Get(rec.Key); ok {
+				res.Hits++
+			} else {
+				res.Misses++
+			}
+		case opPut:
+			res.Puts++
+			target.Put(rec.Key, zero)
+		case opDelete:
+			res.Deletes++
+			target.Delete(rec.Key)
+		default:
+			return res, errors.New("cache: unknown trace operation " + rec.Op)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRecordingCache_Replay tests that a recorded trace replays to the same hit/miss outcomes.
+func TestRecordingCache_Replay(t *testing.T) {
+	var trace bytes.Buffer
+	cache := NewRecordingCache[int, string](NewLRUCache[int, string](3), &trace)
+
+	var want ReplayResult
+	get := func(key int) {
+		want.Gets++
+		if _, ok := cache.Get(key); ok {
+			want.Hits++
+		} else {
+			want.Misses++
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		key := (i * 7) % 5
+		get(key)
+		if i%2 == 0 {
+			cache.Put(key, "v")
+			want.Puts++
+		}
+	}
+	if err := cache.Err(); err != nil {
+		t.Fatalf("Recording failed: %v", err)
+	}
+	if want.Hits == 0 || want.Misses == 0 {
+		t.Fatalf("Expected the workload to produce both hits and misses, got %+v", want)
+	}
+
+	got, err := Replay[int, string](&trace, NewLRUCache[int, string](3))
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if got != want {
+		t.Fatalf("Replay() = %+v; want %+v", got, want)
+	}
+}
+
+// TestReplay_LargerCapacity tests that replaying against a larger cache yields at least as many hits.
+func TestReplay_LargerCapacity(t *testing.T) {
+	var trace bytes.Buffer
+	cache := NewRecordingCache[string, int](NewLRUCache[string, int](1), &trace)
+	for _, key := range []string{"a", "b", "a", "b", "a"} {
+		if _, ok := cache.Get(key); !ok {
+			cache.Put(key, 1)
+		}
+	}
+	data := trace.String()
+
+	small, _ := Replay[string, int](strings.NewReader(data), NewLRUCache[string, int](1))
+	large, _ := Replay[string, int](strings.NewReader(data), NewLRUCache[string, int](2))
+	if small.Hits != 0 || large.Hits != 3 {
+		t.Fatalf("Expected 0 hits with capacity 1 and 3 with capacity 2, got %d and %d", small.Hits, large.Hits)
+	}
+	if r := large.HitRatio(); r != 0.6 {
+		t.Fatalf("Expected hit ratio 0.6, got %v", r)
+	}
+}
+
+// TestRecordingCache_Delete tests that deletes are recorded and replayed.
+func TestRecordingCache_Delete(t *testing.T) {
+	var trace bytes.Buffer
+	cache := NewRecordingCache[string, int](NewMapCache[string, int](), &trace)
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.Delete("a")
+	cache.Get("a")
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected an empty cache, got length %d", n)
+	}
+
+	got, err := Replay[string, int](&trace, NewMapCache[string, int]())
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if want := (ReplayResult{Gets: 1, Puts: 1, Deletes: 1, Misses: 1}); got != want {
+		t.Errorf("Replay() = %+v; want %+v", got, want)
+	}
+}
+
+// TestReplay_InvalidTrace tests that a malformed trace is reported as an error.
+func TestReplay_InvalidTrace(t *testing.T) {
+	if _, err := Replay[int, int](strings.NewReader(`{"op":"del","key":1}`), NewLRUCache[int, int](1)); err == nil {
+		t.Error("Expected error for unknown operation")
+	}
+	if _, err := Replay[int, int](strings.NewReader(`not json`), NewLRUCache[int, int](1)); err == nil {
+		t.Error("Expected error for malformed trace")
+	}
+}
@@ -0,0 +1,197 @@
+// scored.go contains the implementation of the ScoredCache type, a cache
+// whose eviction considers both how recently an entry was used and how close
+// it is to expiring. Near-expiry entries are evicted slightly preferentially
+// even if they were used recently, which smooths reload load.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// ScoreFunc rates how valuable an entry is to keep. recency is the entry's
+// normalized position in the recency order, from 0 for the least recently used
+// entry to 1 for the most recently used. freshness is the entry's remaining
+// time-to-live relative to the cache's default TTL, clamped to [0, 1]. The
+// entry with the lowest score is evicted first.
+type ScoreFunc func(recency, freshness float64) float64
+
+// DefaultScore weighs recency at 0.7 and freshness at 0.3, so recency still
+// dominates but an entry about to expire loses out to a similarly recent one
+// that has plenty of time left.
+func DefaultScore(recency, freshness float64) float64 {
+	return 0.7*recency + 0.3*freshness
+}
+
+// scoredEntry holds a key-value pair and its expiry time.
+type scoredEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// ScoredCache is a fixed-capacity cache with per-entry TTLs that evicts the
+// entry with the lowest ScoreFunc score. Expired entries are never returned and
+// are always evicted before live ones. Choosing a victim scans all entries, so
+// Put on a full cache is O(n). ScoredCache is safe for concurrent use.
+type ScoredCache[K comparable, V any] struct {
+	capacity int                 // Maximum number of items the cache can hold.
+	ttl      time.Duration       // Default time-to-live, also the scale for freshness.
+	score    ScoreFunc           // Rates entries for eviction.
+	list     *list.List          // Recency order, most recently used at the front.
+	dict     map[K]*list.Element // Map for quick access to list elements.
+	now      func() time.Time    // Clock used for expiry; set by SetClock.
+	mu       sync.Mutex          // Mutex to protect concurrent access to the cache.
+}
+
+// NewScoredCache creates a new ScoredCache with the given capacity and default
+// TTL. If score is nil, DefaultScore is used.
+func NewScoredCache[K comparable, V any](capacity int, ttl time.Duration, score ScoreFunc) *ScoredCache[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+	if ttl <= 0 {
+		panic("cache: ttl must be greater than zero")
+	}
+	if score == nil {
+		score = DefaultScore
+	}
+
+	return &ScoredCache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		score:    score,
+		list:     list.New(),
+		dict:     make(map[K]*list.Element, capacity),
+		now:      time.Now,
+	}
+}
+
+// Get retrieves the value associated with the given key if it is present and
+// not expired, marking it as most recently used.
+func (c *ScoredCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.dict[key]; ok {
+		e := elem.Value.(*scoredEntry[K, V])
+		if c.now().Before(e.expires) {
+			c.list.MoveToFront(elem)
+			return e.value, true
+		}
+		c.remove(elem)
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds or updates a key-value pair using the cache's default TTL.
+func (c *ScoredCache[K, V]) Put(key K, val V) {
+	c.PutWithTTL(key, val, c.ttl)
+}
+
+// PutWithTTL adds or updates a key-value pair that expires after ttl. If the
+// cache is full, the lowest-scoring entry is evicted first.
+func (c *ScoredCache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(ttl)
+	if elem, ok := c.dict[key]; ok {
+		e := elem.Value.(*scoredEntry[K, V])
+		e.value = val
+		e.expires = expires
+		c.list.MoveToFront(elem)
+		return
+	}
+
+	if c.list.Len() >= c.capacity {
+		c.evict()
+	}
+
+	elem := c.list.PushFront(&scoredEntry[K, V]{key: key, value: val, expires: expires})
+	c.dict[key] = elem
+}
+
+// Delete removes key from the cache and reports whether it was present. An
+// expired entry counts as absent.
+func (c *ScoredCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.dict[key]
+	if !ok {
+		return false
+	}
+	c.remove(elem)
+	return c.now().Before(elem.Value.(*scoredEntry[K, V]).expires)
+}
+
+// SetClock makes the cache read the time from clk for entry expiry and
+// freshness. Tests pass a clock.Fake to control expiry without sleeping. It
+// must be called before the cache is used.
+func (c *ScoredCache[K, V]) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = clk.Now
+}
+
+// Close does nothing, as a ScoredCache holds no background resources. It
+// exists to satisfy Cache.
+func (c *ScoredCache[K, V]) Close() {}
+
+// Len returns the number of entries in the cache, including expired entries
+// that have not been removed yet.
+func (c *ScoredCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.list.Len()
+}
+
+// evict removes the entry with the lowest score, preferring any expired entry.
+func (c *ScoredCache[K, V]) evict() {
+	now := c.now()
+	n := c.list.Len()
+
+	var victim *list.Element
+	var lowest float64
+	rank := 0
+	for elem := c.list.Back(); elem != nil; elem = elem.Prev() {
+		e := elem.Value.(*scoredEntry[K, V])
+		remaining := e.expires.Sub(now)
+		if remaining <= 0 {
+			victim = elem
+			break
+		}
+
+		recency := 1.0
+		if n > 1 {
+			recency = float64(rank) / float64(n-1)
+		}
+		freshness := float64(remaining) / float64(c.ttl)
+		if freshness > 1 {
+			freshness = 1
+		}
+
+		if s := c.score(recency, freshness); victim == nil || s < lowest {
+			victim, lowest = elem, s
+		}
+		rank++
+	}
+
+	if victim != nil {
+		c.remove(victim)
+	}
+}
+
+// remove deletes elem from the cache.
+func (c *ScoredCache[K, V]) remove(elem *list.Element) {
+	delete(c.dict, elem.Value.(*scoredEntry[K, V]).key)
+	c.list.Remove(elem)
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// TestScoredCache_PutGet tests basic put and get operations.
+func TestScoredCache_PutGet(t *testing.T) {
+	cache := NewScoredCache[string, int](2, time.Hour, nil)
+
+	cache.Put("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"a\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+
+	cache.PutWithTTL("b", 2, -time.Second) // Already expired.
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected expired entry to be missing")
+	}
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("Expected expired entry to be removed on Get, got length %d", n)
+	}
+}
+
+// TestScoredCache_EvictsNearExpiry tests that of two entries with similar recency, the one nearer expiry is evicted.
+func TestScoredCache_EvictsNearExpiry(t *testing.T) {
+	cache := NewScoredCache[int, int](10, time.Hour, nil)
+
+	cache.Put(0, 0)                     // Least recently used, but fresh.
+	cache.PutWithTTL(1, 1, time.Minute) // Slightly more recent, but close to expiry.
+	for i := 2; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	cache.Put(10, 10) // Triggers an eviction.
+
+	if _, ok := cache.Get(1); ok {
+		t.Error("Expected the near-expiry entry to be evicted")
+	}
+	if _, ok := cache.Get(0); !ok {
+		t.Error("Expected the fresh least recently used entry to survive")
+	}
+}
+
+// TestScoredCache_RecencyDominates tests that a near-expiry entry is still kept if it is much more recent.
+func TestScoredCache_RecencyDominates(t *testing.T) {
+	cache := NewScoredCache[int, int](10, time.Hour, nil)
+
+	for i := 0; i < 9; i++ {
+		cache.Put(i, i)
+	}
+	cache.PutWithTTL(9, 9, time.Minute) // Most recent, close to expiry.
+	cache.Put(10, 10)
+
+	if _, ok := cache.Get(0); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get(9); !ok {
+		t.Error("Expected the most recent entry to survive")
+	}
+}
+
+// TestScoredCache_CustomScore tests that a custom score function overrides the default.
+func TestScoredCache_CustomScore(t *testing.T) {
+	// Score only by freshness: the entry closest to expiry goes first.
+	cache := NewScoredCache[string, int](2, time.Hour, func(_, freshness float64) float64 { return freshness })
+
+	cache.PutWithTTL("short", 1, time.Minute)
+	cache.Put("long", 2)
+	cache.Get("short") // Recency is ignored by the custom score.
+	cache.Put("new", 3)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Error("Expected \"short\" to be evicted")
+	}
+	if _, ok := cache.Get("long"); !ok {
+		t.Error("Expected \"long\" to survive")
+	}
+}
+
+// TestScoredCache_ExpiredEvictedFirst tests that expired entries are evicted before live ones.
+func TestScoredCache_ExpiredEvictedFirst(t *testing.T) {
+	cache := NewScoredCache[string, int](2, time.Hour, nil)
+
+	cache.Put("old", 1)
+	cache.PutWithTTL("expired", 2, -time.Second)
+	cache.Put("new", 3)
+
+	if _, ok := cache.Get("old"); !ok {
+		t.Error("Expected \"old\" to survive while an expired entry exists")
+	}
+}
+
+// TestScoredCache_Delete tests that Delete removes entries and treats expired ones as absent.
+func TestScoredCache_Delete(t *testing.T) {
+	cache := NewScoredCache[string, int](2, time.Hour, nil)
+	cache.Put("a", 1)
+	cache.PutWithTTL("expired", 2, -time.Second)
+
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed once")
+	}
+	if cache.Delete("expired") {
+		t.Error("Expected deleting an expired entry to report false")
+	}
+	if n := cache.Len(); n != 0 {
+		t.Errorf("Expected an empty cache, got length %d", n)
+	}
+}
+
+// TestScoredCache_SetClock tests that entries expire and lose freshness as the injected clock advances.
+func TestScoredCache_SetClock(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache := NewScoredCache[int, int](2, time.Hour, func(_, freshness float64) float64 { return freshness })
+	cache.SetClock(clk)
+
+	cache.Put(0, 0)
+	clk.Advance(59 * time.Minute)
+	if _, ok := cache.Get(0); !ok {
+		t.Fatal("Expected the entry to be live before its TTL elapses")
+	}
+	cache.Put(1, 1)
+	cache.Put(2, 2) // Evicts 0, which is about to expire.
+	if _, ok := cache.Get(0); ok {
+		t.Error("Expected the near-expiry entry to be evicted")
+	}
+
+	clk.Advance(time.Hour)
+	if _, ok := cache.Get(1); ok {
+		t.Error("Expected the entry to expire once the clock passes its TTL")
+	}
+}
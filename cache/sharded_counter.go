@@ -0,0 +1,108 @@
+// sharded_counter.go contains the implementation of the ShardedCounter type, a
+// map of counters optimized for heavy concurrent increments. Keys are spread
+// across independently locked shards, and existing counters are updated with
+// atomic operations under a shared read lock so that hot keys do not serialize.
+
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// counterShards is the number of shards used by a ShardedCounter.
+const counterShards = 32
+
+// cacheLine is the cache line size counterShard is padded to.
+const cacheLine = 64
+
+// counterShardSize is the size of the fields of a counterShard, which does
+// not depend on its key type as maps are pointers.
+const counterShardSize = unsafe.Sizeof(sync.RWMutex{}) + unsafe.Sizeof(map[int]*int64(nil))
+
+// counterShard holds the counters for a subset of keys.
+type counterShard[K comparable] struct {
+	mu     sync.RWMutex                                 // Guards the counts map; individual counters are updated atomically.
+	counts map[K]*int64                                 // Counter per key.
+	_      [cacheLine - counterShardSize%cacheLine]byte // Padding to keep shards on separate cache lines.
+}
+
+// ShardedCounter is a concurrent map of int64 counters. Increments to
+// different keys rarely contend, and increments to the same key only take a
+// shared lock. It is safe for concurrent use by multiple goroutines.
+type ShardedCounter[K comparable] struct {
+	shards [counterShards]counterShard[K]
+}
+
+// NewShardedCounter creates a new, empty ShardedCounter.
+func NewShardedCounter[K comparable]() *ShardedCounter[K] {
+	c := &ShardedCounter[K]{}
+	for i := range c.shards {
+		c.shards[i].counts = make(map[K]*int64)
+	}
+	return c
+}
+
+// Inc increments the counter for key by one.
+func (c *ShardedCounter[K]) Inc(key K) {
+	c.Add(key, 1)
+}
+
+// Add adds n to the counter for key, creating it if needed.
+func (c *ShardedCounter[K]) Add(key K, n int64) {
+	s := c.shard(key)
+
+	s.mu.RLock()
+	p, ok := s.counts[key]
+	if ok {
+		atomic.AddInt64(p, n)
+	}
+	s.mu.RUnlock()
+	if ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok = s.counts[key]; !ok { // Another goroutine may have created it meanwhile.
+		p = new(int64)
+		s.counts[key] = p
+	}
+	atomic.AddInt64(p, n)
+}
+
+// Get returns the current value of the counter for key, or zero if the key
+// has never been incremented.
+func (c *ShardedCounter[K]) Get(key K) int64 {
+	s := c.shard(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if p, ok := s.counts[key]; ok {
+		return atomic.LoadInt64(p)
+	}
+	return 0
+}
+
+// Snapshot returns a copy of all counters. Each shard is read consistently,
+// but increments racing with Snapshot may or may not be included.
+func (c *ShardedCounter[K]) Snapshot() map[K]int64 {
+	snap := make(map[K]int64)
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.RLock()
+		for key, p := range s.counts {
+			snap[key] = atomic.LoadInt64(p)
+		}
+		s.mu.RUnlock()
+	}
+	return snap
+}
+
+// shard returns the shard responsible for key.
+func (c *ShardedCounter[K]) shard(key K) *counterShard[K] {
+	return &c.shards[hashKey(key)%counterShards]
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// mutexCounter is a naive mutex-guarded map used as a baseline for ShardedCounter.
+type mutexCounter[K comparable] struct {
+	mu     sync.Mutex
+	counts map[K]int64
+}
+
+func (c *mutexCounter[K]) Inc(key K) {
+	c.mu.Lock()
+	c.counts[key]++
+	c.mu.Unlock()
+}
+
+// benchmarkKeys returns a set of endpoint-like keys.
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "/api/endpoint/" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+// BenchmarkShardedCounter_Inc benchmarks concurrent increments on a ShardedCounter.
+func BenchmarkShardedCounter_Inc(b *testing.B) {
+	keys := benchmarkKeys(64)
+	c := NewShardedCounter[string]()
+	var n int64
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddInt64(&n, 1))
+		for pb.Next() {
+			c.Inc(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+// BenchmarkMutexCounter_Inc benchmarks concurrent increments on a single mutex-guarded map.
+func BenchmarkMutexCounter_Inc(b *testing.B) {
+	keys := benchmarkKeys(64)
+	c := &mutexCounter[string]{counts: make(map[string]int64)}
+	var n int64
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddInt64(&n, 1))
+		for pb.Next() {
+			c.Inc(keys[i%len(keys)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// TestShardedCounter_IncAddGet tests basic counter operations.
+func TestShardedCounter_IncAddGet(t *testing.T) {
+	c := NewShardedCounter[string]()
+
+	if v := c.Get("missing"); v != 0 {
+		t.Fatalf("c.Get(\"missing\") = %d; want %d", v, 0)
+	}
+
+	c.Inc("a")
+	c.Inc("a")
+	c.Add("a", 5)
+	c.Add("b", -3)
+
+	if v := c.Get("a"); v != 7 {
+		t.Fatalf("c.Get(\"a\") = %d; want %d", v, 7)
+	}
+	if v := c.Get("b"); v != -3 {
+		t.Fatalf("c.Get(\"b\") = %d; want %d", v, -3)
+	}
+}
+
+// TestShardedCounter_ConcurrentSnapshot tests that Snapshot sums are correct under concurrent increments.
+func TestShardedCounter_ConcurrentSnapshot(t *testing.T) {
+	const goroutines, perGoroutine, keys = 16, 1000, 10
+	c := NewShardedCounter[int]()
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				c.Inc((g + i) % keys)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	snap := c.Snapshot()
+	if len(snap) != keys {
+		t.Fatalf("Expected %d keys in snapshot, got %d", keys, len(snap))
+	}
+	var total int64
+	for _, v := range snap {
+		total += v
+	}
+	if total != goroutines*perGoroutine {
+		t.Fatalf("Expected snapshot total %d, got %d", goroutines*perGoroutine, total)
+	}
+}
+
+// TestCounterShard_Size tests that counter shards fill whole cache lines.
+func TestCounterShard_Size(t *testing.T) {
+	if size := unsafe.Sizeof(counterShard[string]{}); size%cacheLine != 0 {
+		t.Errorf("Expected a multiple of %d bytes, got %d", cacheLine, size)
+	}
+}
@@ -0,0 +1,183 @@
+// sharded_lru.go contains the implementation of the ShardedLRUCache type, an
+// LRU cache split into independently locked shards to reduce lock contention
+// under heavy concurrent access, and the partitioning strategies that route
+// keys to shards.
+
+package cache
+
+import (
+	"runtime"
+	"sort"
+)
+
+// Partitioner maps key to the index of the shard holding it, in [0, shards).
+type Partitioner[K comparable] func(key K, shards int) int
+
+// ordered is satisfied by the types whose values can be compared with <.
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// HashPartition is a Partitioner that spreads keys evenly across shards by
+// hashing them, regardless of how the keys relate to each other.
+func HashPartition[K comparable](key K, shards int) int {
+	return int(hashKey(key) % uint64(shards))
+}
+
+// RangePartition returns a Partitioner that routes keys by range, so adjacent
+// keys share a shard. bounds must be sorted in ascending order: keys below
+// bounds[0] go to shard 0, keys in [bounds[i-1], bounds[i]) to shard i, and
+// keys at or above the last bound to shard len(bounds). With shards-1 bounds
+// every shard covers one range; if there are fewer shards than ranges, the
+// upper ranges all go to the last shard.
+func RangePartition[K ordered](bounds ...K) Partitioner[K] {
+	if !sort.SliceIsSorted(bounds, func(i, j int) bool { return bounds[i] < bounds[j] }) {
+		panic("cache: range partition bounds must be sorted")
+	}
+	bounds = append([]K(nil), bounds...)
+
+	return func(key K, shards int) int {
+		i := sort.Search(len(bounds), func(i int) bool { return key < bounds[i] })
+		if i >= shards {
+			i = shards - 1
+		}
+		return i
+	}
+}
+
+// ShardedLRUCache spreads keys across a fixed number of LRUCache shards, by
+// hashing them unless another Partitioner is chosen. Each shard has its own
+// lock and an equal share of the total capacity, and evicts its own least
+// recently used entry when full, so the eviction order is only approximately
+// LRU across the whole cache. It is safe for concurrent use by multiple
+// goroutines.
+type ShardedLRUCache[K comparable, V any] struct {
+	shards    []*LRUCache[K, V] // Independent caches holding disjoint sets of keys.
+	partition Partitioner[K]    // Maps a key to its shard.
+}
+
+// NewShardedLRUCache creates a new ShardedLRUCache holding at most capacity
+// entries split across the given number of shards. When capacity is not a
+// multiple of shards, the first shards get one extra entry each. A shards of
+// zero picks DefaultShards, capped at capacity. Keys are routed with
+// HashPartition.
+func NewShardedLRUCache[K comparable, V any](capacity, shards int) *ShardedLRUCache[K, V] {
+	return NewShardedLRUCacheFunc[K, V](capacity, shards, HashPartition[K])
+}
+
+// NewShardedLRUCacheFunc is like NewShardedLRUCache but routes keys to shards
+// with partition, for example one returned by RangePartition.
+func NewShardedLRUCacheFunc[K comparable, V any](capacity, shards int, partition Partitioner[K]) *ShardedLRUCache[K, V] {
+	if partition == nil {
+		panic("cache: partition function must not be nil")
+	}
+	if shards == 0 {
+		shards = DefaultShards()
+		if shards > capacity {
+			shards = capacity
+		}
+	}
+	if shards <= 0 {
+		panic("cache: shards must be greater than zero")
+	}
+	if capacity < shards {
+		panic("cache: capacity must be at least the number of shards")
+	}
+
+	c := &ShardedLRUCache[K, V]{
+		shards:    make([]*LRUCache[K, V], shards),
+		partition: partition,
+	}
+	for i := range c.shards {
+		n := capacity / shards
+		if i < capacity%shards {
+			n++
+		}
+		c.shards[i] = NewLRUCache[K, V](n)
+	}
+	return c
+}
+
+// DefaultShards returns the number of shards NewShardedLRUCache uses when
+// given zero: four per GOMAXPROCS, which keeps contention on any one shard's
+// lock low even when every processor is accessing the cache.
+func DefaultShards() int {
+	return runtime.GOMAXPROCS(0) * 4
+}
+
+// Get retrieves the value associated with the given key from its shard and
+// marks it as most recently used within that shard.
+func (c *ShardedLRUCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// Put adds or updates a key-value pair in its shard, evicting the shard's least
+// recently used entry if the shard is full.
+func (c *ShardedLRUCache[K, V]) Put(key K, val V) {
+	c.shard(key).Put(key, val)
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *ShardedLRUCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+// Compute atomically updates key in its shard; see LRUCache.Compute. Only the
+// shard holding key is locked while fn runs.
+func (c *ShardedLRUCache[K, V]) Compute(key K, fn func(old V, existed bool) (V, bool)) (V, bool) {
+	return c.shard(key).Compute(key, fn)
+}
+
+// Len returns the number of entries across all shards. Shards are counted one
+// at a time, so the result is not a consistent snapshot under concurrent use.
+func (c *ShardedLRUCache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Close stops the janitors of all shards, if any are running.
+func (c *ShardedLRUCache[K, V]) Close() {
+	for _, s := range c.shards {
+		s.Close()
+	}
+}
+
+// Capacity returns the total capacity across all shards.
+func (c *ShardedLRUCache[K, V]) Capacity() int {
+	n := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		n += s.capacity
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Resize changes the total capacity, splitting it across the shards as
+// NewShardedLRUCache does and resizing each shard in turn with
+// LRUCache.Resize. Shards that shrink evict their least recently used
+// entries; all other entries are kept. newCapacity must be at least the
+// number of shards.
+func (c *ShardedLRUCache[K, V]) Resize(newCapacity int) {
+	if newCapacity < len(c.shards) {
+		panic("cache: capacity must be at least the number of shards")
+	}
+
+	for i, s := range c.shards {
+		n := newCapacity / len(c.shards)
+		if i < newCapacity%len(c.shards) {
+			n++
+		}
+		s.Resize(n)
+	}
+}
+
+// shard returns the shard responsible for key.
+func (c *ShardedLRUCache[K, V]) shard(key K) *LRUCache[K, V] {
+	return c.shards[c.partition(key, len(c.shards))]
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// BenchmarkShardedLRUCache_Concurrent benchmarks concurrent Put and Get with a single shard, which
+// behaves like a plain LRUCache, and with many shards, to show the effect of spreading the lock.
+func BenchmarkShardedLRUCache_Concurrent(b *testing.B) {
+	for _, shards := range []int{1, 32} {
+		b.Run(strconv.Itoa(shards)+"Shards", func(b *testing.B) {
+			cache := NewShardedLRUCache[int, string](1000, shards)
+			var workers int64
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				// Each goroutine walks its own key sequence, starting at a different offset, so the
+				// benchmark measures the cache's lock rather than a shared key counter.
+				key := int(atomic.AddInt64(&workers, 1)) * 131
+				for pb.Next() {
+					key = (key + 7) % 1000
+					cache.Put(key, "v")
+					_, _ = cache.Get(key)
+				}
+			})
+		})
+	}
+}
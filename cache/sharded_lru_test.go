@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestNewShardedLRUCache tests that the capacity is split across shards without losing any.
+func TestNewShardedLRUCache(t *testing.T) {
+	cache := NewShardedLRUCache[int, int](10, 4)
+	if n := len(cache.shards); n != 4 {
+		t.Fatalf("Expected 4 shards, got %d", n)
+	}
+	if n := cache.Capacity(); n != 10 {
+		t.Fatalf("cache.Capacity() = %d; want %d", n, 10)
+	}
+	want := []int{3, 3, 2, 2}
+	for i, s := range cache.shards {
+		if s.capacity != want[i] {
+			t.Fatalf("Shard %d has capacity %d; want %d", i, s.capacity, want[i])
+		}
+	}
+}
+
+// TestNewShardedLRUCache_DefaultShards tests that zero shards picks the default, capped at the capacity.
+func TestNewShardedLRUCache_DefaultShards(t *testing.T) {
+	capacity := DefaultShards() * 10
+	c := NewShardedLRUCache[int, int](capacity, 0)
+	if len(c.shards) != DefaultShards() || c.Capacity() != capacity {
+		t.Errorf("Expected %d shards holding %d entries, got %d shards holding %d", DefaultShards(), capacity, len(c.shards), c.Capacity())
+	}
+
+	small := NewShardedLRUCache[int, int](1, 0)
+	if len(small.shards) != 1 {
+		t.Errorf("Expected the default to be capped at a capacity of 1, got %d shards", len(small.shards))
+	}
+}
+
+// TestShardedLRUCache_GetPut tests that values can be stored and retrieved across shards.
+func TestShardedLRUCache_GetPut(t *testing.T) {
+	cache := NewShardedLRUCache[string, int](64, 8)
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for i, k := range keys {
+		cache.Put(k, i)
+	}
+	for i, k := range keys {
+		if v, ok := cache.Get(k); !ok || v != i {
+			t.Fatalf("cache.Get(%q) = %d, %v; want %d, %v", k, v, ok, i, true)
+		}
+	}
+	if n := cache.Len(); n != len(keys) {
+		t.Fatalf("cache.Len() = %d; want %d", n, len(keys))
+	}
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed exactly once")
+	}
+}
+
+// TestShardedLRUCache_EvictionPerShard tests that a full shard evicts its own entries without affecting others.
+func TestShardedLRUCache_EvictionPerShard(t *testing.T) {
+	cache := NewShardedLRUCacheFunc[int, int](4, 2, func(key, shards int) int {
+		return key % shards // Even keys to shard 0, odd keys to shard 1.
+	})
+
+	cache.Put(1, 1)
+	cache.Put(0, 0)
+	cache.Put(2, 2)
+	cache.Put(4, 4) // Shard 0 is full and evicts 0, its least recently used entry.
+
+	if _, ok := cache.Get(0); ok {
+		t.Fatal("Expected key 0 to be evicted from its shard")
+	}
+	for _, key := range []int{1, 2, 4} {
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("Expected key %d to be cached", key)
+		}
+	}
+	if n := cache.Len(); n != 3 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 3)
+	}
+}
+
+// TestShardedLRUCache_Resize tests that Resize splits the new capacity across shards and keeps surviving entries.
+func TestShardedLRUCache_Resize(t *testing.T) {
+	cache := NewShardedLRUCacheFunc[int, int](8, 2, func(key, shards int) int {
+		return key % shards
+	})
+	for i := 0; i < 8; i++ {
+		cache.Put(i, i)
+	}
+
+	cache.Resize(5)
+	if c := cache.Capacity(); c != 5 {
+		t.Fatalf("cache.Capacity() = %d; want %d", c, 5)
+	}
+	if n := cache.Len(); n != 5 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 5)
+	}
+	for _, key := range []int{4, 6, 7, 5} { // Shard 0 keeps 3 entries, shard 1 keeps 2.
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("Expected key %d to survive the resize", key)
+		}
+	}
+
+	cache.Resize(10)
+	cache.Put(8, 8)
+	if n := cache.Len(); n != 6 {
+		t.Fatalf("cache.Len() = %d; want %d", n, 6)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected Resize below the number of shards to panic")
+		}
+	}()
+	cache.Resize(1)
+}
+
+// TestShardedLRUCache_Compute tests that concurrent read-modify-writes of a counter are not lost.
+func TestShardedLRUCache_Compute(t *testing.T) {
+	cache := NewShardedLRUCache[string, int](8, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Compute("hits", func(old int, _ bool) (int, bool) { return old + 1, true })
+		}()
+	}
+	wg.Wait()
+
+	if v, ok := cache.Get("hits"); !ok || v != 50 {
+		t.Fatalf("cache.Get(\"hits\") = %d, %v; want 50, true", v, ok)
+	}
+	if _, ok := cache.Compute("hits", func(int, bool) (int, bool) { return 0, false }); ok || cache.Len() != 0 {
+		t.Fatal("Expected Compute returning keep false to delete the key")
+	}
+}
+
+// TestRangePartition tests that a contiguous key range is routed to the same shard.
+func TestRangePartition(t *testing.T) {
+	partition := RangePartition(100, 200)
+	cases := []struct {
+		key, shard int
+	}{
+		{-5, 0}, {0, 0}, {99, 0},
+		{100, 1}, {150, 1}, {199, 1},
+		{200, 2}, {1000, 2},
+	}
+	for _, c := range cases {
+		if got := partition(c.key, 3); got != c.shard {
+			t.Errorf("partition(%d) = %d; want %d", c.key, got, c.shard)
+		}
+	}
+	if got := partition(1000, 2); got != 1 {
+		t.Errorf("Expected keys beyond the last shard to be clamped to shard 1, got %d", got)
+	}
+
+	cache := NewShardedLRUCacheFunc[int, int](30, 3, partition)
+	for key := 100; key < 110; key++ {
+		cache.Put(key, key)
+	}
+	if n := cache.shards[1].Len(); n != 10 {
+		t.Fatalf("Expected all 10 keys in shard 1, got %d", n)
+	}
+}
+
+// TestRangePartition_Unsorted tests that unsorted bounds are rejected.
+func TestRangePartition_Unsorted(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected RangePartition to panic for unsorted bounds")
+		}
+	}()
+	RangePartition("m", "c")
+}
+
+// TestHashPartition tests that hash partitioning spreads a contiguous key range across shards.
+func TestHashPartition(t *testing.T) {
+	counts := make([]int, 4)
+	for key := 100; key < 200; key++ {
+		counts[HashPartition(key, 4)]++
+	}
+	for shard, n := range counts {
+		if n < 10 {
+			t.Errorf("Expected shard %d to get a fair share of 100 keys, got %d", shard, n)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// string_lru.go contains the implementation of the StringLRUCache type, an
+// LRU cache specialized for string keys and optimized for memory footprint.
+//
+// Instead of indexing entries by key in a map[string]int, StringLRUCache
+// indexes them by a 64-bit hash of the key in a map[uint64]int32 and links
+// entries with 32-bit indices. This roughly halves the index's per-entry
+// overhead for short keys. The tradeoff is CPU: every lookup hashes the key
+// and compares it against the stored one, and keys whose hash collides with a
+// different resident key fall back to a secondary map[string]int32, which is
+// slower but keeps results exact.
+
+package cache
+
+import (
+	"math"
+	"sync"
+)
+
+// stringEntry holds a key-value pair for the StringLRUCache together with the
+// arena indices of its neighbours in the recency list.
+type stringEntry[V any] struct {
+	key   string
+	value V
+	prev  int32
+	next  int32
+}
+
+// StringLRUCache is a Least Recently Used cache for string keys with a compact
+// internal representation. It behaves like LRUCache[string, V] and is safe for
+// concurrent use by multiple goroutines.
+type StringLRUCache[V any] struct {
+	capacity   int                 // Maximum number of items the cache can hold.
+	entries    []stringEntry[V]    // Arena of entries; index 0 is the recency list sentinel.
+	index      map[uint64]int32    // Map from key hash to arena index.
+	collisions map[string]int32    // Keys whose hash slot is held by a different key, allocated on demand.
+	free       []int32             // Arena indices of removed entries available for reuse.
+	hash       func(string) uint64 // Key hash function; replaceable in tests to force collisions.
+	mu         sync.Mutex          // Mutex to protect concurrent access to the cache.
+}
+
+// NewStringLRUCache creates a new StringLRUCache with the given capacity.
+func NewStringLRUCache[V any](capacity int) *StringLRUCache[V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+	if capacity >= math.MaxInt32 {
+		panic("cache: capacity too large for StringLRUCache")
+	}
+
+	return &StringLRUCache[V]{
+		capacity: capacity,
+		entries:  make([]stringEntry[V], 1, capacity+1),
+		index:    make(map[uint64]int32, capacity),
+		hash:     fnv1a,
+	}
+}
+
+// Get retrieves the value associated with the given key from the cache.
+// If the key is found in the cache, Get returns the value and true.
+// Otherwise, it returns the zero value for V and false.
+func (c *StringLRUCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.lookup(key, c.hash(key)); ok {
+		c.moveToFront(i)
+		return c.entries[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds a key-value pair to the cache. If the key already exists, its value
+// is updated. If adding a new key exceeds the cache's capacity, the least recently
+// used item is evicted.
+func (c *StringLRUCache[V]) Put(key string, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h := c.hash(key)
+	if i, ok := c.lookup(key, h); ok {
+		c.entries[i].value = val
+		c.moveToFront(i)
+		return
+	}
+
+	if c.len() >= c.capacity {
+		c.evict()
+	}
+
+	var i int32
+	if n := len(c.free); n > 0 {
+		i = c.free[n-1]
+		c.free = c.free[:n-1]
+	} else {
+		c.entries = append(c.entries, stringEntry[V]{})
+		i = int32(len(c.entries) - 1)
+	}
+	c.entries[i].key = key
+	c.entries[i].value = val
+	c.link(i)
+
+	if _, taken := c.index[h]; !taken {
+		c.index[h] = i
+		return
+	}
+	if c.collisions == nil {
+		c.collisions = make(map[string]int32)
+	}
+	c.collisions[key] = i
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *StringLRUCache[V]) Delete(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.lookup(key, c.hash(key))
+	if ok {
+		c.remove(i)
+	}
+	return ok
+}
+
+// Close does nothing, as a StringLRUCache holds no background resources. It
+// exists to satisfy Cache.
+func (c *StringLRUCache[V]) Close() {}
+
+// Len returns the number of items in the cache.
+func (c *StringLRUCache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.len()
+}
+
+// len returns the number of items in the cache. The caller must hold c.mu.
+func (c *StringLRUCache[V]) len() int {
+	return len(c.entries) - 1 - len(c.free)
+}
+
+// lookup returns the arena index of key, whose hash is h.
+func (c *StringLRUCache[V]) lookup(key string, h uint64) (int32, bool) {
+	if i, ok := c.index[h]; ok && c.entries[i].key == key {
+		return i, true
+	}
+	if c.collisions != nil {
+		i, ok := c.collisions[key]
+		return i, ok
+	}
+	return 0, false
+}
+
+// evict removes the least recently used item from the cache.
+func (c *StringLRUCache[V]) evict() {
+	if oldest := c.entries[sentinel].prev; oldest != sentinel {
+		c.remove(oldest)
+	}
+}
+
+// remove drops the entry at arena index i from the index and the recency
+// list and makes its slot available for reuse.
+func (c *StringLRUCache[V]) remove(i int32) {
+	key := c.entries[i].key
+	if h := c.hash(key); c.index[h] == i {
+		delete(c.index, h)
+	} else {
+		delete(c.collisions, key)
+	}
+
+	c.unlink(i)
+	c.entries[i] = stringEntry[V]{} // Release the key and value.
+	c.free = append(c.free, i)
+}
+
+// link inserts the entry at arena index i at the front of the recency list.
+func (c *StringLRUCache[V]) link(i int32) {
+	front := c.entries[sentinel].next
+	c.entries[i].prev = sentinel
+	c.entries[i].next = front
+	c.entries[front].prev = i
+	c.entries[sentinel].next = i
+}
+
+// unlink removes the entry at arena index i from the recency list.
+func (c *StringLRUCache[V]) unlink(i int32) {
+	e := &c.entries[i]
+	c.entries[e.prev].next = e.next
+	c.entries[e.next].prev = e.prev
+}
+
+// moveToFront marks the entry at arena index i as most recently used.
+func (c *StringLRUCache[V]) moveToFront(i int32) {
+	if c.entries[sentinel].next == i {
+		return
+	}
+	c.unlink(i)
+	c.link(i)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// bytesPerEntryKeys is the number of entries used to measure per-entry memory.
+const bytesPerEntryKeys = 1 << 18
+
+// heapInUse returns the current heap usage after a garbage collection.
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// stringKeys returns n short string keys.
+func stringKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "k" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+// BenchmarkLRUCache_BytesPerEntry measures the memory footprint per entry of LRUCache with string keys.
+func BenchmarkLRUCache_BytesPerEntry(b *testing.B) {
+	keys := stringKeys(bytesPerEntryKeys)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		cache := NewLRUCache[string, int](len(keys))
+		for j, key := range keys {
+			cache.Put(key, j)
+		}
+		b.ReportMetric(float64(heapInUse()-before)/float64(len(keys)), "bytes/entry")
+		runtime.KeepAlive(cache)
+	}
+}
+
+// BenchmarkStringLRUCache_BytesPerEntry measures the memory footprint per entry of StringLRUCache.
+func BenchmarkStringLRUCache_BytesPerEntry(b *testing.B) {
+	keys := stringKeys(bytesPerEntryKeys)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		cache := NewStringLRUCache[int](len(keys))
+		for j, key := range keys {
+			cache.Put(key, j)
+		}
+		b.ReportMetric(float64(heapInUse()-before)/float64(len(keys)), "bytes/entry")
+		runtime.KeepAlive(cache)
+	}
+}
+
+// BenchmarkStringLRUCache_Get benchmarks the performance of the Get operation.
+func BenchmarkStringLRUCache_Get(b *testing.B) {
+	keys := stringKeys(1000)
+	cache := NewStringLRUCache[int](len(keys))
+	for i, key := range keys {
+		cache.Put(key, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cache.Get(keys[i%len(keys)])
+	}
+}
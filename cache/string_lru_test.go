@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestStringLRUCache_PutGet tests basic put and get operations.
+func TestStringLRUCache_PutGet(t *testing.T) {
+	cache := NewStringLRUCache[string](2)
+
+	cache.Put("key1", "val1")
+	if v, ok := cache.Get("key1"); !ok || v != "val1" {
+		t.Fatalf("cache.Get(\"key1\") = %v, %v; want %v, %v", v, ok, "val1", true)
+	}
+
+	cache.Put("key1", "val1-updated")
+	if v, ok := cache.Get("key1"); !ok || v != "val1-updated" {
+		t.Fatalf("cache.Get(\"key1\") after update = %v, %v; want %v, %v", v, ok, "val1-updated", true)
+	}
+
+	cache.Put("key2", "val2")
+	cache.Put("key3", "val3") // This should evict "key1"
+	if _, ok := cache.Get("key1"); ok {
+		t.Fatal("Expected \"key1\" to be evicted")
+	}
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("Expected length 2, got %d", n)
+	}
+}
+
+// TestStringLRUCache_EvictionOrder tests the LRU eviction policy.
+func TestStringLRUCache_EvictionOrder(t *testing.T) {
+	cache := NewStringLRUCache[int](2)
+
+	cache.Put("1", 1)
+	cache.Put("2", 2)
+	cache.Put("3", 3) // Evicts "1"
+
+	if _, ok := cache.Get("1"); ok {
+		t.Fatal("Expected key \"1\" to be evicted")
+	}
+
+	cache.Get("2")    // This access should make "2" the most recently used
+	cache.Put("4", 4) // Evicts "3"
+
+	if _, ok := cache.Get("3"); ok {
+		t.Fatal("Expected key \"3\" to be evicted")
+	}
+}
+
+// TestStringLRUCache_HashCollisions tests that keys with colliding hashes are kept apart.
+func TestStringLRUCache_HashCollisions(t *testing.T) {
+	cache := NewStringLRUCache[int](3)
+	cache.hash = func(string) uint64 { return 42 } // Every key collides.
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	for i, key := range []string{"a", "b", "c"} { // Gets in insertion order keep the recency order.
+		if v, ok := cache.Get(key); !ok || v != i+1 {
+			t.Fatalf("cache.Get(%q) = %v, %v; want %v, %v", key, v, ok, i+1, true)
+		}
+	}
+
+	cache.Put("d", 4) // Evicts "a", which holds the primary hash slot.
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected \"a\" to be evicted")
+	}
+	cache.Put("e", 5) // Evicts "b" from the collision map and takes the free primary slot.
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected \"b\" to be evicted")
+	}
+	for i, key := range []string{"c", "d", "e"} {
+		if v, ok := cache.Get(key); !ok || v != i+3 {
+			t.Fatalf("cache.Get(%q) = %v, %v; want %v, %v", key, v, ok, i+3, true)
+		}
+	}
+}
+
+// TestStringLRUCache_Delete tests that deleting colliding keys keeps the remaining keys reachable.
+func TestStringLRUCache_Delete(t *testing.T) {
+	cache := NewStringLRUCache[int](3)
+	cache.hash = func(string) uint64 { return 42 } // Every key collides.
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed once")
+	}
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"b\") = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+	if !cache.Delete("b") || cache.Len() != 0 {
+		t.Fatalf("Expected an empty cache after deleting \"b\", got length %d", cache.Len())
+	}
+
+	cache.Put("c", 3) // Reuses a freed slot.
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Fatalf("cache.Get(\"c\") = %v, %v; want %v, %v", v, ok, 3, true)
+	}
+}
+
+// TestStringLRUCache_Concurrency tests the cache's thread-safety by performing parallel reads and writes.
+func TestStringLRUCache_Concurrency(t *testing.T) {
+	cache := NewStringLRUCache[int](100)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		cache.Put(strconv.Itoa(i), i)
+	}
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(key int) {
+			defer wg.Done()
+			cache.Put(strconv.Itoa(key), key*10)
+		}(i)
+
+		go func(key int) {
+			defer wg.Done()
+			cache.Get(strconv.Itoa(key))
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 50; i++ {
+		if v, ok := cache.Get(strconv.Itoa(i)); !ok || v != i*10 {
+			t.Fatalf("cache.Get(%d) = %v, %v; want %v, %v", i, v, ok, i*10, true)
+		}
+	}
+}
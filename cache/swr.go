@@ -0,0 +1,215 @@
+// swr.go contains the implementation of the SWRCache type, a cache with
+// stale-while-revalidate semantics whose Get never blocks on the loader.
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// Source reports where a value returned by SWRCache.GetWithSource came from.
+type Source int
+
+const (
+	// Missing means no value was returned because the loader failed.
+	Missing Source = iota
+	// CacheFresh means the value was served from the cache while fresh.
+	CacheFresh
+	// CacheStale means a stale value was served while a background refresh runs.
+	CacheStale
+	// Loaded means the value was loaded synchronously on a miss or an expired entry.
+	Loaded
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case CacheFresh:
+		return "CacheFresh"
+	case CacheStale:
+		return "CacheStale"
+	case Loaded:
+		return "Loaded"
+	default:
+		return "Missing"
+	}
+}
+
+// swrEntry is a cached value together with the time it was loaded.
+type swrEntry[V any] struct {
+	value  V
+	loaded time.Time
+}
+
+// SWRCache is an LRU cache that refreshes entries in the background using a
+// loader. An entry is fresh for freshFor after it was loaded, then stale for a
+// further staleFor, then expired:
+//
+//   - a fresh entry is returned as is;
+//   - a stale entry is returned immediately while a background refresh runs;
+//   - on a miss or an expired entry, Get returns false immediately and a
+//     background load is started, so a later Get can find the value.
+//
+// At most one load or refresh runs per key at a time. If a load fails, the
+// error is passed to the SetOnRefreshError callback, if any, and the current
+// entry is kept until it expires. staleFor thus bounds how old a value served
+// during an outage of the loader's backend can get.
+// SWRCache is safe for concurrent use by multiple goroutines.
+type SWRCache[K comparable, V any] struct {
+	lru      *LRUCache[K, swrEntry[V]] // Cached values and their load times.
+	freshFor time.Duration             // How long a loaded value is fresh.
+	staleFor time.Duration             // How long a value may be served stale after it is no longer fresh.
+	loader   func(key K) (V, error)    // Loads the value for a key.
+	onError  func(key K, err error)    // Called when a background load fails; may be nil.
+	mu       sync.Mutex                // Mutex to protect inflight, onError and now.
+	inflight map[K]struct{}            // Keys with a load or refresh in progress.
+	loads    sync.WaitGroup            // Tracks background loads; used by tests.
+	now      func() time.Time          // Clock used to age entries; set by SetClock.
+}
+
+// NewSWRCache creates a new SWRCache with the given capacity, freshness and
+// staleness windows, and loader.
+func NewSWRCache[K comparable, V any](capacity int, freshFor, staleFor time.Duration, loader func(K) (V, error)) *SWRCache[K, V] {
+	if freshFor <= 0 || staleFor < 0 {
+		panic("cache: freshFor must be greater than zero and staleFor must not be negative")
+	}
+	if loader == nil {
+		panic("cache: loader must not be nil")
+	}
+
+	return &SWRCache[K, V]{
+		lru:      NewLRUCache[K, swrEntry[V]](capacity),
+		freshFor: freshFor,
+		staleFor: staleFor,
+		loader:   loader,
+		inflight: make(map[K]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value for key if it is fresh or stale, scheduling a
+// background refresh for stale values. On a miss or an expired entry it
+// returns false and schedules a background load. Get never calls the loader
+// synchronously.
+func (c *SWRCache[K, V]) Get(key K) (V, bool) {
+	e, ok := c.lru.Get(key)
+	if !ok {
+		c.refresh(key)
+		var zero V
+		return zero, false
+	}
+
+	switch age := c.now().Sub(e.loaded); {
+	case age < c.freshFor:
+		return e.value, true
+	case age < c.freshFor+c.staleFor:
+		c.refresh(key)
+		return e.value, true
+	default:
+		c.refresh(key)
+		var zero V
+		return zero, false
+	}
+}
+
+// GetWithSource is like Get but also reports where the value came from. Unlike
+// Get, on a miss or an expired entry it calls the loader synchronously and
+// stores the result, returning Loaded, or Missing together with the loader's
+// error.
+func (c *SWRCache[K, V]) GetWithSource(key K) (V, Source, error) {
+	if e, ok := c.lru.Get(key); ok {
+		switch age := c.now().Sub(e.loaded); {
+		case age < c.freshFor:
+			return e.value, CacheFresh, nil
+		case age < c.freshFor+c.staleFor:
+			c.refresh(key)
+			return e.value, CacheStale, nil
+		}
+	}
+
+	val, err := c.loader(key)
+	if err != nil {
+		var zero V
+		return zero, Missing, err
+	}
+	c.Put(key, val)
+	return val, Loaded, nil
+}
+
+// SetClock makes the cache read the time from clk to age entries. Tests pass
+// a clock.Fake to move entries between the fresh, stale and expired windows
+// without sleeping. It must be called before the cache is used.
+func (c *SWRCache[K, V]) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = clk.Now
+	c.lru.SetClock(clk)
+}
+
+// SetOnRefreshError sets fn to be called with the key and error whenever a
+// background load or refresh fails, for logging or metrics; such errors are
+// otherwise dropped. fn runs on the goroutine of the failed load, without any
+// lock held.
+func (c *SWRCache[K, V]) SetOnRefreshError(fn func(key K, err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onError = fn
+}
+
+// Put stores a freshly loaded value for key.
+func (c *SWRCache[K, V]) Put(key K, val V) {
+	c.lru.Put(key, swrEntry[V]{value: val, loaded: c.now()})
+}
+
+// Delete removes key from the cache and reports whether it was present. A
+// load of key already in progress still stores its result afterwards.
+func (c *SWRCache[K, V]) Delete(key K) bool {
+	return c.lru.Delete(key)
+}
+
+// Len returns the number of entries in the cache, including expired ones.
+func (c *SWRCache[K, V]) Len() int {
+	return c.lru.Len()
+}
+
+// Close waits for background loads and refreshes in progress to finish. The
+// cache remains usable afterwards.
+func (c *SWRCache[K, V]) Close() {
+	c.loads.Wait()
+}
+
+// refresh starts a background load of key unless one is already running.
+func (c *SWRCache[K, V]) refresh(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.inflight[key]; ok {
+		return
+	}
+	c.inflight[key] = struct{}{}
+	onError := c.onError
+
+	c.loads.Add(1)
+	go func() {
+		defer c.loads.Done()
+		defer func() {
+			c.mu.Lock()
+			delete(c.inflight, key)
+			c.mu.Unlock()
+		}()
+
+		val, err := c.loader(key)
+		if err != nil {
+			if onError != nil {
+				onError(key, err)
+			}
+			return
+		}
+		c.Put(key, val)
+	}()
+}
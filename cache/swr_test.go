@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// newTestSWRCache returns an SWRCache with a fake clock and a loader that
+// returns the number of times it has been called.
+func newTestSWRCache(loaderDelay time.Duration) (*SWRCache[string, int], *clock.Fake, *int64) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	var calls int64
+	c := NewSWRCache(10, time.Minute, time.Minute, func(string) (int, error) {
+		time.Sleep(loaderDelay)
+		return int(atomic.AddInt64(&calls, 1)), nil
+	})
+	c.SetClock(clk)
+	return c, clk, &calls
+}
+
+// TestSWRCache_Fresh tests that a fresh entry is served without calling the loader.
+func TestSWRCache_Fresh(t *testing.T) {
+	c, clk, calls := newTestSWRCache(0)
+	c.Put("k", 42)
+	clk.Advance(30 * time.Second)
+
+	if v, ok := c.Get("k"); !ok || v != 42 {
+		t.Fatalf("c.Get(\"k\") = %v, %v; want %v, %v", v, ok, 42, true)
+	}
+	c.loads.Wait()
+	if n := atomic.LoadInt64(calls); n != 0 {
+		t.Fatalf("Expected no loader calls, got %d", n)
+	}
+}
+
+// TestSWRCache_StaleWhileRevalidate tests that a stale entry is served immediately while a single refresh runs.
+func TestSWRCache_StaleWhileRevalidate(t *testing.T) {
+	c, clk, calls := newTestSWRCache(50 * time.Millisecond)
+	c.Put("k", 0)
+	clk.Advance(90 * time.Second) // Stale, not expired.
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			if v, ok := c.Get("k"); !ok || v != 0 {
+				t.Errorf("c.Get(\"k\") = %v, %v; want stale %v, %v", v, ok, 0, true)
+			}
+			if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+				t.Errorf("Expected Get not to block on the loader, took %v", elapsed)
+			}
+		}()
+	}
+	wg.Wait()
+	c.loads.Wait()
+
+	if n := atomic.LoadInt64(calls); n != 1 {
+		t.Fatalf("Expected exactly 1 background refresh, got %d", n)
+	}
+	if v, ok := c.Get("k"); !ok || v != 1 {
+		t.Fatalf("c.Get(\"k\") after refresh = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+}
+
+// TestSWRCache_Expired tests that a miss or an expired entry returns false immediately and loads in the background.
+func TestSWRCache_Expired(t *testing.T) {
+	c, clk, _ := newTestSWRCache(0)
+
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+	c.loads.Wait()
+	if v, ok := c.Get("k"); !ok || v != 1 {
+		t.Fatalf("c.Get(\"k\") after load = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+
+	clk.Advance(3 * time.Minute) // Past fresh and stale windows.
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("Expected a miss for an expired entry")
+	}
+	c.loads.Wait()
+	if v, ok := c.Get("k"); !ok || v != 2 {
+		t.Fatalf("c.Get(\"k\") after reload = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestSWRCache_LoaderError tests that a failed refresh keeps serving the stale value.
+func TestSWRCache_LoaderError(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	c := NewSWRCache(10, time.Minute, time.Minute, func(string) (int, error) {
+		return 0, errors.New("unavailable")
+	})
+	c.SetClock(clk)
+
+	c.Put("k", 7)
+	clk.Advance(90 * time.Second)
+	c.Get("k")
+	c.loads.Wait()
+
+	if v, ok := c.Get("k"); !ok || v != 7 {
+		t.Fatalf("c.Get(\"k\") = %v, %v; want stale %v, %v", v, ok, 7, true)
+	}
+}
+
+// TestSWRCache_OnRefreshError tests that a failed background refresh is reported to the callback.
+func TestSWRCache_OnRefreshError(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	c := NewSWRCache(10, time.Minute, time.Minute, func(string) (int, error) {
+		return 0, errUnavailable
+	})
+
+	var mu sync.Mutex
+	var failed []string
+	c.SetOnRefreshError(func(key string, err error) {
+		if !errors.Is(err, errUnavailable) {
+			t.Errorf("Expected %v, got %v", errUnavailable, err)
+		}
+		mu.Lock()
+		failed = append(failed, key)
+		mu.Unlock()
+	})
+
+	c.Get("k")
+	c.loads.Wait()
+	if len(failed) != 1 || failed[0] != "k" {
+		t.Fatalf("Expected one failure for k, got %v", failed)
+	}
+}
+
+// TestSWRCache_GetWithSource tests each source outcome.
+func TestSWRCache_GetWithSource(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1000, 0))
+	errDown := errors.New("down")
+	fail := false
+	var calls int64
+	c := NewSWRCache(10, time.Minute, time.Minute, func(string) (int, error) {
+		if fail {
+			return 0, errDown
+		}
+		return int(atomic.AddInt64(&calls, 1)), nil
+	})
+	c.SetClock(clk)
+
+	check := func(wantVal int, wantSrc Source) {
+		t.Helper()
+		v, src, err := c.GetWithSource("k")
+		if err != nil || v != wantVal || src != wantSrc {
+			t.Fatalf("c.GetWithSource(\"k\") = %v, %v, %v; want %v, %v, nil", v, src, err, wantVal, wantSrc)
+		}
+	}
+
+	check(1, Loaded) // Miss: loaded synchronously.
+	check(1, CacheFresh)
+
+	clk.Advance(90 * time.Second)
+	check(1, CacheStale)
+	c.loads.Wait() // The background refresh stores 2.
+	check(2, CacheFresh)
+
+	clk.Advance(3 * time.Minute)
+	check(3, Loaded) // Expired: loaded synchronously.
+
+	clk.Advance(3 * time.Minute)
+	fail = true
+	if _, src, err := c.GetWithSource("k"); src != Missing || !errors.Is(err, errDown) {
+		t.Fatalf("c.GetWithSource(\"k\") = _, %v, %v; want Missing, %v", src, err, errDown)
+	}
+	if s := Missing.String(); s != "Missing" {
+		t.Fatalf("Missing.String() = %q", s)
+	}
+}
+
+// TestSWRCache_DeleteClose tests that Delete removes an entry and Close waits for background loads.
+func TestSWRCache_DeleteClose(t *testing.T) {
+	c, _, calls := newTestSWRCache(10 * time.Millisecond)
+	c.Put("k", 42)
+	if !c.Delete("k") || c.Len() != 0 {
+		t.Fatalf("Expected \"k\" to be deleted, got length %d", c.Len())
+	}
+
+	c.Get("k") // Starts a background load.
+	c.Close()
+	if n := atomic.LoadInt64(calls); n != 1 {
+		t.Fatalf("Expected Close to wait for the load, got %d loader calls", n)
+	}
+	if v, ok := c.lru.Get("k"); !ok || v.value != 1 {
+		t.Errorf("Expected the loaded value to be stored, got %v, %v", v.value, ok)
+	}
+}
@@ -0,0 +1,145 @@
+// tiered.go contains the implementation of the TieredCache type, which layers
+// an in-process LRUCache in front of a shared remote store such as Redis or
+// memcached, and the adapters for plugging such stores in.
+
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RemoteStore is a key-value store shared between processes, used as the
+// second tier of a TieredCache. Get reports whether key was found; a missing
+// key is not an error. Implementations must be safe for concurrent use.
+type RemoteStore[K comparable, V any] interface {
+	Get(ctx context.Context, key K) (V, bool, error)
+	Set(ctx context.Context, key K, val V) error
+	Delete(ctx context.Context, key K) error
+}
+
+// ByteStore is a remote store of byte values under string keys, the shape of
+// the API offered by Redis and memcached clients. Wrapping a client in a
+// ByteStore usually takes a few lines, and NewCodecStore turns it into a
+// RemoteStore for any key and value types.
+type ByteStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, val []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// codecStore adapts a ByteStore to a RemoteStore; see NewCodecStore.
+type codecStore[K comparable, V any] struct {
+	store     ByteStore
+	key       func(K) string
+	marshal   func(V) ([]byte, error)
+	unmarshal func([]byte) (V, error)
+}
+
+// NewCodecStore returns a RemoteStore that stores its entries in store. key
+// maps a key to its string form in the store, for example by formatting it
+// with a namespace prefix, and marshal and unmarshal convert values to and
+// from bytes, for example with encoding/json.
+func NewCodecStore[K comparable, V any](store ByteStore, key func(K) string,
+	marshal func(V) ([]byte, error), unmarshal func([]byte) (V, error)) RemoteStore[K, V] {
+	if store == nil || key == nil || marshal == nil || unmarshal == nil {
+		panic("cache: codec store arguments must not be nil")
+	}
+
+	return &codecStore[K, V]{store: store, key: key, marshal: marshal, unmarshal: unmarshal}
+}
+
+func (s *codecStore[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	var zero V
+	b, ok, err := s.store.Get(ctx, s.key(key))
+	if err != nil || !ok {
+		return zero, false, err
+	}
+	val, err := s.unmarshal(b)
+	if err != nil {
+		return zero, false, err
+	}
+	return val, true, nil
+}
+
+func (s *codecStore[K, V]) Set(ctx context.Context, key K, val V) error {
+	b, err := s.marshal(val)
+	if err != nil {
+		return err
+	}
+	return s.store.Set(ctx, s.key(key), b)
+}
+
+func (s *codecStore[K, V]) Delete(ctx context.Context, key K) error {
+	return s.store.Delete(ctx, s.key(key))
+}
+
+// TieredCache is a two-level cache: an in-process LRUCache backed by a
+// RemoteStore shared with other processes. It is a WriteThroughCache in
+// write-through mode over the LRUCache: reads check the local tier first and
+// fall back to the remote one, filling the local tier on a remote hit, and
+// writes and deletes go to both tiers. Other processes' writes only become
+// visible locally once the local entry is evicted or expires, which bounds
+// how stale the local tier can be by its TTL. A value read remotely is not
+// cached if the key was set or deleted through the same TieredCache while it
+// was being read. TieredCache is safe for concurrent use if the remote store
+// is.
+type TieredCache[K comparable, V any] struct {
+	local *LRUCache[K, V]          // In-process tier.
+	wt    *WriteThroughCache[K, V] // Write-through decorator of local over the remote store.
+}
+
+// NewTieredCache creates a new TieredCache whose local tier holds up to
+// capacity entries for at most localTTL each, or until evicted if localTTL is
+// zero. With a TTL, the local tier runs a janitor until Close is called.
+func NewTieredCache[K comparable, V any](capacity int, localTTL time.Duration, remote RemoteStore[K, V]) *TieredCache[K, V] {
+	if localTTL < 0 {
+		panic("cache: local TTL must not be negative")
+	}
+	if remote == nil {
+		panic("cache: remote store must not be nil")
+	}
+
+	var local *LRUCache[K, V]
+	if localTTL > 0 {
+		local = NewLRUCacheWithTTL[K, V](capacity, localTTL)
+	} else {
+		local = NewLRUCache[K, V](capacity)
+	}
+	return &TieredCache[K, V]{local: local, wt: NewWriteThroughCache[K, V](local, remote)}
+}
+
+// Local returns the in-process tier, for example to inspect its statistics or
+// to set an eviction callback.
+func (c *TieredCache[K, V]) Local() *LRUCache[K, V] {
+	return c.local
+}
+
+// Get retrieves the value associated with the given key, from the local tier
+// if it is there and otherwise from the remote store, storing a value found
+// remotely in the local tier unless key was written meanwhile. It returns the
+// remote store's error, if any.
+func (c *TieredCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	return c.wt.Get(ctx, key)
+}
+
+// Set writes the key-value pair to the remote store and then to the local
+// tier. If the remote write fails, the local entry for key is removed instead,
+// as the remote store may or may not hold the new value. After Close, Set
+// returns ErrCacheClosed.
+func (c *TieredCache[K, V]) Set(ctx context.Context, key K, val V) error {
+	return c.wt.Put(ctx, key, val)
+}
+
+// Delete removes key from the remote store and then from the local tier. The
+// local entry is removed even if the remote delete fails. After Close, Delete
+// returns ErrCacheClosed.
+func (c *TieredCache[K, V]) Delete(ctx context.Context, key K) error {
+	return c.wt.Delete(ctx, key)
+}
+
+// Close stops the local tier's janitor, if it is running. It does not close
+// the remote store.
+func (c *TieredCache[K, V]) Close() {
+	c.wt.Close()
+}
@@ -0,0 +1,114 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edast/go-utils/cache"
+)
+
+// errRedisNil mirrors the error Redis clients return for a missing key.
+var errRedisNil = errors.New("redis: nil")
+
+// redisClient stands in for a Redis client such as go-redis, with the same
+// method shapes, so that the example runs without a server.
+type redisClient struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (r *redisClient) Get(_ context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.data[key]
+	if !ok {
+		return "", errRedisNil
+	}
+	return v, nil
+}
+
+func (r *redisClient) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (r *redisClient) Del(_ context.Context, keys ...string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, key := range keys {
+		if _, ok := r.data[key]; ok {
+			delete(r.data, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// redisStore adapts a Redis client to cache.ByteStore. Entries expire in
+// Redis after ttl.
+type redisStore struct {
+	client *redisClient
+	ttl    time.Duration
+}
+
+func (s redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := s.client.Get(ctx, key)
+	if errors.Is(err, errRedisNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(v), true, nil
+}
+
+func (s redisStore) Set(ctx context.Context, key string, val []byte) error {
+	return s.client.Set(ctx, key, string(val), s.ttl)
+}
+
+func (s redisStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Del(ctx, key)
+	return err
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+// ExampleNewCodecStore layers an in-process cache in front of Redis, storing
+// users as JSON under "user:<id>" keys.
+func ExampleNewCodecStore() {
+	ctx := context.Background()
+	client := &redisClient{data: make(map[string]string)}
+
+	remote := cache.NewCodecStore[int, user](redisStore{client: client, ttl: time.Hour},
+		func(id int) string { return "user:" + strconv.Itoa(id) },
+		func(u user) ([]byte, error) { return json.Marshal(u) },
+		func(b []byte) (user, error) {
+			var u user
+			err := json.Unmarshal(b, &u)
+			return u, err
+		})
+	users := cache.NewTieredCache[int, user](1000, time.Minute, remote)
+	defer users.Close()
+
+	if err := users.Set(ctx, 1, user{Name: "Ada"}); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(client.data["user:1"])
+
+	client.data["user:2"] = `{"name":"Grace"}` // Written by another process.
+	u, ok, err := users.Get(ctx, 2)
+	fmt.Println(u.Name, ok, err)
+	// Output:
+	// {"name":"Ada"}
+	// Grace true <nil>
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/cache/clock"
+)
+
+// fakeByteStore is an in-memory ByteStore that counts its calls and can be
+// made to fail.
+type fakeByteStore struct {
+	mu       sync.Mutex
+	data     map[string][]byte
+	gets     int
+	err      error
+	afterGet func() // If not nil, called by Get after reading, before returning.
+}
+
+func (s *fakeByteStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	s.gets++
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, false, s.err
+	}
+	b, ok := s.data[key]
+	afterGet := s.afterGet
+	s.mu.Unlock()
+
+	if afterGet != nil {
+		afterGet()
+	}
+	return b, ok, nil
+}
+
+func (s *fakeByteStore) Set(_ context.Context, key string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = val
+	return nil
+}
+
+func (s *fakeByteStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.data, key)
+	return nil
+}
+
+// newTestTieredCache returns a TieredCache over a fakeByteStore using JSON values and "user:"-prefixed keys.
+func newTestTieredCache(capacity int, ttl time.Duration) (*TieredCache[int, string], *fakeByteStore) {
+	store := &fakeByteStore{data: make(map[string][]byte)}
+	remote := NewCodecStore[int, string](store,
+		func(key int) string { return "user:" + strconv.Itoa(key) },
+		func(val string) ([]byte, error) { return json.Marshal(val) },
+		func(b []byte) (string, error) {
+			var val string
+			err := json.Unmarshal(b, &val)
+			return val, err
+		})
+	return NewTieredCache[int, string](capacity, ttl, remote), store
+}
+
+// TestTieredCache_GetFillsLocal tests that a remote hit is stored locally and later served without the remote store.
+func TestTieredCache_GetFillsLocal(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestTieredCache(2, 0)
+	defer cache.Close()
+	store.data["user:1"] = []byte(`"alice"`)
+
+	for i := 0; i < 2; i++ {
+		val, ok, err := cache.Get(ctx, 1)
+		if err != nil || !ok || val != "alice" {
+			t.Fatalf("cache.Get(1) = %q, %v, %v; want alice, true, nil", val, ok, err)
+		}
+	}
+	if store.gets != 1 {
+		t.Errorf("Expected 1 remote get, got %d", store.gets)
+	}
+
+	if _, ok, err := cache.Get(ctx, 2); ok || err != nil {
+		t.Errorf("cache.Get(2) = _, %v, %v; want false, nil", ok, err)
+	}
+}
+
+// TestTieredCache_SetDelete tests that writes and deletes reach both tiers.
+func TestTieredCache_SetDelete(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestTieredCache(2, 0)
+	defer cache.Close()
+
+	if err := cache.Set(ctx, 1, "bob"); err != nil {
+		t.Fatalf("cache.Set failed: %v", err)
+	}
+	if string(store.data["user:1"]) != `"bob"` {
+		t.Errorf("Expected the remote store to hold \"bob\", got %s", store.data["user:1"])
+	}
+	if val, ok := cache.Local().Get(1); !ok || val != "bob" {
+		t.Errorf("Expected the local tier to hold bob, got %q, %v", val, ok)
+	}
+
+	if err := cache.Delete(ctx, 1); err != nil {
+		t.Fatalf("cache.Delete failed: %v", err)
+	}
+	if _, ok := store.data["user:1"]; ok {
+		t.Error("Expected key 1 to be deleted remotely")
+	}
+	if cache.Local().Contains(1) {
+		t.Error("Expected key 1 to be deleted locally")
+	}
+}
+
+// TestTieredCache_Closed tests that writes after Close return ErrCacheClosed and leave the remote store untouched.
+func TestTieredCache_Closed(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestTieredCache(2, time.Minute)
+	cache.Close()
+	cache.Close() // Closing twice is harmless.
+
+	if err := cache.Set(ctx, 1, "bob"); !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("cache.Set() error = %v; want %v", err, ErrCacheClosed)
+	}
+	if err := cache.Delete(ctx, 1); !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("cache.Delete() error = %v; want %v", err, ErrCacheClosed)
+	}
+	if len(store.data) != 0 {
+		t.Errorf("Expected the remote store to stay empty, got %v", store.data)
+	}
+}
+
+// TestTieredCache_RemoteErrors tests that remote errors are returned and a failed Set drops the local entry.
+func TestTieredCache_RemoteErrors(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestTieredCache(2, 0)
+	defer cache.Close()
+	if err := cache.Set(ctx, 1, "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	store.err = errors.New("connection refused")
+	if err := cache.Set(ctx, 1, "new"); !errors.Is(err, store.err) {
+		t.Fatalf("cache.Set() error = %v; want %v", err, store.err)
+	}
+	if cache.Local().Contains(1) {
+		t.Error("Expected a failed Set to drop the local entry")
+	}
+	if _, ok, err := cache.Get(ctx, 1); ok || !errors.Is(err, store.err) {
+		t.Errorf("cache.Get() = _, %v, %v; want false, %v", ok, err, store.err)
+	}
+}
+
+// TestTieredCache_GetRacingWrites tests that a remote read overtaken by a Delete or Set is not cached locally.
+func TestTieredCache_GetRacingWrites(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name  string
+		write func(c *TieredCache[int, string]) error
+		want  string // Expected local value afterwards; empty if absent.
+	}{
+		{"Delete", func(c *TieredCache[int, string]) error { return c.Delete(ctx, 1) }, ""},
+		{"Set", func(c *TieredCache[int, string]) error { return c.Set(ctx, 1, "v2") }, "v2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cache, store := newTestTieredCache(2, 0)
+			defer cache.Close()
+			store.data["user:1"] = []byte(`"v1"`)
+
+			read, release := make(chan struct{}), make(chan struct{})
+			store.afterGet = func() {
+				close(read)
+				<-release
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				if val, _, _ := cache.Get(ctx, 1); val != "v1" {
+					t.Errorf("Expected the racing Get to return v1, got %q", val)
+				}
+			}()
+
+			<-read // The Get has read v1 but not cached it yet.
+			store.mu.Lock()
+			store.afterGet = nil
+			store.mu.Unlock()
+			if err := tc.write(cache); err != nil {
+				t.Fatal(err)
+			}
+			close(release)
+			<-done
+
+			if val, _ := cache.Local().Peek(1); val != tc.want {
+				t.Fatalf("Expected the local tier to hold %q, got %q", tc.want, val)
+			}
+		})
+	}
+}
+
+// TestTieredCache_LocalTTL tests that local entries expire after the local TTL and are refetched from the remote store.
+func TestTieredCache_LocalTTL(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Unix(1000, 0))
+	cache, store := newTestTieredCache(2, time.Minute)
+	defer cache.Close()
+	cache.Local().SetClock(clk)
+
+	if err := cache.Set(ctx, 1, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	store.data["user:1"] = []byte(`"v2"`) // Written by another process.
+	if val, _, _ := cache.Get(ctx, 1); val != "v1" {
+		t.Fatalf("Expected the local value v1, got %q", val)
+	}
+
+	clk.Advance(time.Minute)
+	if val, _, _ := cache.Get(ctx, 1); val != "v2" {
+		t.Errorf("Expected the remote value v2 after the local TTL, got %q", val)
+	}
+}
@@ -0,0 +1,195 @@
+// two_queue.go contains the implementation of the TwoQueueCache type, the
+// full 2Q algorithm of Johnson and Shasha. New entries wait in a small FIFO
+// queue and are only promoted to the main LRU queue if they are requested
+// again after leaving it, so one-off scans cannot flush the main queue.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Default queue ratios of a TwoQueueCache, as recommended by the 2Q paper.
+const (
+	DefaultTwoQueueInRatio  = 0.25 // Share of the capacity held by A1in.
+	DefaultTwoQueueOutRatio = 0.50 // Number of A1out ghost keys, relative to the capacity.
+)
+
+// twoQueue identifies which of the three 2Q queues an entry is on.
+type twoQueue int
+
+const (
+	queueIn   twoQueue = iota // A1in: resident entries seen once, in FIFO order.
+	queueOut                  // A1out: ghost keys recently evicted from A1in.
+	queueMain                 // Am: resident entries seen again, in LRU order.
+)
+
+// twoQueueEntry holds a key-value pair, or only a key while it is a ghost,
+// together with the queue it is on.
+type twoQueueEntry[K comparable, V any] struct {
+	key   K
+	value V
+	queue twoQueue
+	elem  *list.Element
+}
+
+// TwoQueueCache is a fixed-capacity cache implementing the full 2Q policy. A
+// new key enters the FIFO queue A1in. When it is pushed out of A1in, only its
+// key is remembered on the ghost queue A1out; if the key is put again while
+// remembered there, it enters the main LRU queue Am. Entries that are used
+// only once, such as those of a scan, therefore pass through A1in without
+// displacing the working set in Am. Hits in A1in do not reorder it.
+// TwoQueueCache is safe for concurrent use by multiple goroutines.
+type TwoQueueCache[K comparable, V any] struct {
+	capacity int                        // Maximum number of resident items, in A1in and Am together.
+	maxIn    int                        // Size A1in may grow to before it is evicted from first.
+	maxOut   int                        // Maximum number of ghost keys in A1out.
+	queues   [3]*list.List              // A1in, A1out and Am, newest or most recently used at the front.
+	dict     map[K]*twoQueueEntry[K, V] // Map for quick access to resident and ghost entries.
+	mu       sync.Mutex                 // Mutex to protect concurrent access to the cache.
+}
+
+// NewTwoQueueCache creates a new TwoQueueCache with the given capacity and the
+// default queue ratios.
+func NewTwoQueueCache[K comparable, V any](capacity int) *TwoQueueCache[K, V] {
+	return NewTwoQueueCacheParams[K, V](capacity, DefaultTwoQueueInRatio, DefaultTwoQueueOutRatio)
+}
+
+// NewTwoQueueCacheParams creates a new TwoQueueCache with the given capacity.
+// inRatio is the share of the capacity A1in may take before it is evicted from
+// first, and must be between 0 and 1. outRatio sets how many ghost keys A1out
+// remembers relative to the capacity, and must not be negative. Both sizes are
+// at least one.
+func NewTwoQueueCacheParams[K comparable, V any](capacity int, inRatio, outRatio float64) *TwoQueueCache[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be greater than zero")
+	}
+	if inRatio <= 0 || inRatio >= 1 || outRatio < 0 {
+		panic("cache: inRatio must be between 0 and 1 and outRatio must not be negative")
+	}
+
+	c := &TwoQueueCache[K, V]{
+		capacity: capacity,
+		maxIn:    maxInt(1, int(float64(capacity)*inRatio)),
+		maxOut:   maxInt(1, int(float64(capacity)*outRatio)),
+		dict:     make(map[K]*twoQueueEntry[K, V], capacity),
+	}
+	for i := range c.queues {
+		c.queues[i] = list.New()
+	}
+	return c
+}
+
+// Get retrieves the value associated with the given key. A hit in Am marks
+// the entry as most recently used; a hit in A1in leaves the queue unchanged.
+func (c *TwoQueueCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.dict[key]; ok && e.queue != queueOut {
+		if e.queue == queueMain {
+			c.queues[queueMain].MoveToFront(e.elem)
+		}
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put adds or updates a key-value pair. A key remembered on A1out enters Am;
+// any other new key enters A1in. If the cache is full, an entry is evicted
+// first: the oldest of A1in if A1in exceeds its share, otherwise the least
+// recently used of Am.
+func (c *TwoQueueCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	switch {
+	case ok && e.queue == queueMain:
+		e.value = val
+		c.queues[queueMain].MoveToFront(e.elem)
+	case ok && e.queue == queueIn:
+		e.value = val
+	case ok: // A ghost on A1out. Forget it first, so reclaim cannot drop it from a full A1out.
+		c.remove(e)
+		c.reclaim()
+		c.insert(key, val, queueMain)
+	default:
+		c.reclaim()
+		c.insert(key, val, queueIn)
+	}
+}
+
+// Delete removes key from the cache, forgetting it on A1out as well, and
+// reports whether it was resident.
+func (c *TwoQueueCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.dict[key]
+	if !ok {
+		return false
+	}
+	c.remove(e)
+	return e.queue != queueOut
+}
+
+// Len returns the number of resident entries in the cache.
+func (c *TwoQueueCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.resident()
+}
+
+// Close does nothing, as a TwoQueueCache holds no background resources. It
+// exists to satisfy Cache.
+func (c *TwoQueueCache[K, V]) Close() {}
+
+// reclaim makes room for one more resident entry if the cache is full. An
+// entry evicted from A1in is remembered on A1out, whose oldest ghost is
+// forgotten once A1out is full.
+func (c *TwoQueueCache[K, V]) reclaim() {
+	if c.resident() < c.capacity {
+		return
+	}
+
+	if in := c.queues[queueIn]; in.Len() > c.maxIn || c.queues[queueMain].Len() == 0 {
+		e := in.Back().Value.(*twoQueueEntry[K, V])
+		var zero V
+		e.value = zero // Ghosts keep only the key.
+		c.move(e, queueOut)
+		if out := c.queues[queueOut]; out.Len() > c.maxOut {
+			c.remove(out.Back().Value.(*twoQueueEntry[K, V]))
+		}
+		return
+	}
+	c.remove(c.queues[queueMain].Back().Value.(*twoQueueEntry[K, V]))
+}
+
+// resident returns the number of entries holding values.
+func (c *TwoQueueCache[K, V]) resident() int {
+	return c.queues[queueIn].Len() + c.queues[queueMain].Len()
+}
+
+// insert adds a resident entry for key at the front of queue q.
+func (c *TwoQueueCache[K, V]) insert(key K, val V, q twoQueue) {
+	e := &twoQueueEntry[K, V]{key: key, value: val, queue: q}
+	e.elem = c.queues[q].PushFront(e)
+	c.dict[key] = e
+}
+
+// move moves e to the front of queue q.
+func (c *TwoQueueCache[K, V]) move(e *twoQueueEntry[K, V], q twoQueue) {
+	c.queues[e.queue].Remove(e.elem)
+	e.queue = q
+	e.elem = c.queues[q].PushFront(e)
+}
+
+// remove deletes e from its queue and the map.
+func (c *TwoQueueCache[K, V]) remove(e *twoQueueEntry[K, V]) {
+	c.queues[e.queue].Remove(e.elem)
+	delete(c.dict, e.key)
+}
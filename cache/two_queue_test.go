@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestTwoQueueCache_PutGet tests basic put, get, update and delete operations.
+func TestTwoQueueCache_PutGet(t *testing.T) {
+	cache := NewTwoQueueCache[string, int](4)
+
+	cache.Put("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"a\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	cache.Put("a", 10)
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Fatalf("cache.Get(\"a\") after update = %v, %v; want %v, %v", v, ok, 10, true)
+	}
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("Expected deleting \"a\" to succeed once")
+	}
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Expected an empty cache, got length %d", n)
+	}
+}
+
+// TestTwoQueueCache_GhostPromotion tests that a key put again while on A1out enters the main queue.
+func TestTwoQueueCache_GhostPromotion(t *testing.T) {
+	cache := NewTwoQueueCacheParams[int, int](4, 0.25, 1)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i) // The fifth put pushes 0 out of A1in onto A1out.
+	}
+	if _, ok := cache.Get(0); ok {
+		t.Fatal("Expected 0 to be evicted from A1in")
+	}
+	if e := cache.dict[0]; e == nil || e.queue != queueOut {
+		t.Fatal("Expected 0 to be remembered on A1out")
+	}
+
+	cache.Put(0, 100)
+	if e := cache.dict[0]; e.queue != queueMain {
+		t.Fatalf("Expected 0 to enter the main queue, got queue %d", e.queue)
+	}
+	if v, ok := cache.Get(0); !ok || v != 100 {
+		t.Fatalf("cache.Get(0) = %v, %v; want %v, %v", v, ok, 100, true)
+	}
+	if n := cache.Len(); n != 4 {
+		t.Errorf("Expected length 4, got %d", n)
+	}
+}
+
+// TestTwoQueueCache_ScanResistance tests that a one-off scan does not flush entries in the main queue.
+func TestTwoQueueCache_ScanResistance(t *testing.T) {
+	cache := NewTwoQueueCacheParams[int, int](10, 0.25, 1)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	for i := 100; i < 110; i++ {
+		cache.Put(i, i) // Pushes 0-4 onto A1out.
+	}
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i) // Readmitted to the main queue.
+	}
+
+	for i := 200; i < 300; i++ {
+		cache.Put(i, i)
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := cache.Get(i); !ok {
+			t.Errorf("Expected hot key %d to survive the scan", i)
+		}
+	}
+}
+
+// TestTwoQueueCache_Bounds tests that the queue sizes stay within their limits under a random workload.
+func TestTwoQueueCache_Bounds(t *testing.T) {
+	cache := NewTwoQueueCache[int, int](16)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		key := rng.Intn(64)
+		switch rng.Intn(10) {
+		case 0:
+			cache.Delete(key)
+		case 1, 2, 3:
+			cache.Put(key, i)
+		default:
+			if _, ok := cache.Get(key); !ok {
+				cache.Put(key, i)
+			}
+		}
+
+		in, out, main := cache.queues[queueIn].Len(), cache.queues[queueOut].Len(), cache.queues[queueMain].Len()
+		if in+main > 16 || out > cache.maxOut || len(cache.dict) != in+out+main {
+			t.Fatalf("2Q bounds violated after %d operations: A1in=%d A1out=%d Am=%d map=%d", i+1, in, out, main, len(cache.dict))
+		}
+	}
+}
+
+// TestNewTwoQueueCacheParams_Invalid tests that out-of-range ratios are rejected.
+func TestNewTwoQueueCacheParams_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected NewTwoQueueCacheParams to panic for an inRatio of 1")
+		}
+	}()
+	NewTwoQueueCacheParams[int, int](10, 1, 0.5)
+}
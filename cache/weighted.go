@@ -0,0 +1,79 @@
+// weighted.go contains the constructors for LRUCaches that bound the total
+// weight of their entries, such as their size in bytes, instead of the number
+// of entries.
+
+package cache
+
+import (
+	"math"
+	"time"
+	"unsafe"
+)
+
+// Sizer is implemented by values that know their own size in bytes. Caches
+// created with NewSizedLRUCache use it to weigh entries automatically.
+type Sizer interface {
+	SizeBytes() int64
+}
+
+// NewWeightedLRUCache creates an LRUCache that evicts least recently used
+// entries whenever the total weight of its entries, as reported by weigh,
+// exceeds budget. The number of entries is not limited. A value heavier than
+// the whole budget is never stored: putting one leaves the other entries in
+// place and removes any previous value of its key.
+func NewWeightedLRUCache[K comparable, V any](budget int64, weigh func(key K, val V) int64) *LRUCache[K, V] {
+	if budget <= 0 {
+		panic("cache: budget must be greater than zero")
+	}
+	if weigh == nil {
+		panic("cache: weigh function must not be nil")
+	}
+
+	return &LRUCache[K, V]{
+		capacity: math.MaxInt,
+		entries:  make([]entry[K, V], 1), // Grown on demand, as the number of entries is unknown.
+		dict:     make(map[K]int),
+		weigh:    weigh,
+		budget:   budget,
+		now:      time.Now,
+	}
+}
+
+// NewSizedLRUCache creates a weighted LRUCache whose budget is maxBytes. Values
+// implementing Sizer are weighed by SizeBytes; any other value weighs 1.
+func NewSizedLRUCache[K comparable, V any](maxBytes int64) *LRUCache[K, V] {
+	return NewWeightedLRUCache(maxBytes, func(_ K, val V) int64 {
+		if s, ok := any(val).(Sizer); ok {
+			return s.SizeBytes()
+		}
+		return 1
+	})
+}
+
+// TotalWeight returns the summed weight of all entries currently in the cache.
+// In a cache that is not weighted every entry weighs 1, so it equals the
+// number of entries.
+func (c *LRUCache[K, V]) TotalWeight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.weigh == nil {
+		return int64(len(c.dict))
+	}
+	return c.weight
+}
+
+// EntryOverhead returns a rough estimate, in bytes, of the memory the cache
+// uses per entry for its own bookkeeping: the arena slot holding the key and
+// value, plus the index map's slot for the key. Memory referenced by keys and
+// values, such as string or slice contents, is not included. Multiplying it by
+// the number of entries gives a lower bound to correlate with RSS.
+func (c *LRUCache[K, V]) EntryOverhead() int64 {
+	var e entry[K, V]
+	var k K
+	var i int
+	// Go maps store keys and values inline in buckets with one byte of hash
+	// per slot, and keep buckets at most about 80% full.
+	mapSlot := float64(unsafe.Sizeof(k)+unsafe.Sizeof(i)+1) / 0.8
+	return int64(unsafe.Sizeof(e)) + int64(mapSlot)
+}
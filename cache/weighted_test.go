@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// blob is a test value that reports its size through the Sizer interface.
+type blob []byte
+
+func (b blob) SizeBytes() int64 { return int64(len(b)) }
+
+// TestSizedLRUCache_ByteBudget tests that eviction keeps the total size within the byte budget.
+func TestSizedLRUCache_ByteBudget(t *testing.T) {
+	cache := NewSizedLRUCache[string, blob](100)
+
+	cache.Put("a", make(blob, 40))
+	cache.Put("b", make(blob, 40))
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected \"a\" to fit within the budget")
+	}
+
+	cache.Put("c", make(blob, 40)) // 120 bytes: evicts "b", the least recently used.
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("Expected \"b\" to be evicted")
+	}
+	if cache.weight != 80 {
+		t.Fatalf("Expected total weight 80, got %d", cache.weight)
+	}
+
+	cache.Put("a", make(blob, 90)) // Growing "a" pushes out "c".
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("Expected \"c\" to be evicted after \"a\" grew")
+	}
+	if cache.weight != 90 {
+		t.Fatalf("Expected total weight 90, got %d", cache.weight)
+	}
+
+	cache.Put("huge", make(blob, 200)) // Larger than the whole budget.
+	if _, ok := cache.Get("huge"); ok {
+		t.Fatal("Expected an entry exceeding the budget not to be stored")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected an entry exceeding the budget not to evict \"a\"")
+	}
+	if cache.weight != 90 {
+		t.Fatalf("Expected total weight 90, got %d", cache.weight)
+	}
+
+	cache.Put("a", make(blob, 200)) // The stale value of "a" must not survive.
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected \"a\" to be removed by an update exceeding the budget")
+	}
+	if cache.weight != 0 {
+		t.Fatalf("Expected total weight 0, got %d", cache.weight)
+	}
+}
+
+// TestSizedLRUCache_DefaultWeight tests that values not implementing Sizer weigh 1.
+func TestSizedLRUCache_DefaultWeight(t *testing.T) {
+	cache := NewSizedLRUCache[int, string](3)
+
+	for i := 0; i < 5; i++ {
+		cache.Put(i, "value")
+	}
+
+	if s := cache.Stats(); s.Length != 3 {
+		t.Fatalf("Expected 3 entries of weight 1, got %d", s.Length)
+	}
+	if _, ok := cache.Get(1); ok {
+		t.Fatal("Expected key 1 to be evicted")
+	}
+}
+
+// TestWeightedLRUCache_Invalidate tests that removing entries releases their weight.
+func TestWeightedLRUCache_Invalidate(t *testing.T) {
+	cache := NewWeightedLRUCache(10, func(_ string, v int) int64 { return int64(v) })
+
+	cache.PutTagged("a", 4, "t")
+	cache.PutTagged("b", 5, "t")
+	cache.InvalidateTag("t")
+	if cache.weight != 0 {
+		t.Fatalf("Expected total weight 0, got %d", cache.weight)
+	}
+
+	cache.Put("c", 6)
+	cache.UpdateIf("c", func(int) bool { return true }, 3)
+	if cache.weight != 3 {
+		t.Fatalf("Expected total weight 3 after UpdateIf, got %d", cache.weight)
+	}
+}
+
+// TestWeightedLRUCache_TotalWeight tests that TotalWeight tracks inserts, updates and evictions.
+func TestWeightedLRUCache_TotalWeight(t *testing.T) {
+	cache := NewWeightedLRUCache(100, func(_ string, v int) int64 { return int64(v) })
+
+	steps := []struct {
+		key  string
+		val  int
+		want int64
+	}{
+		{"a", 30, 30},
+		{"b", 40, 70},
+		{"a", 10, 50},  // Update shrinks "a".
+		{"c", 50, 100}, // Exactly at the budget.
+		{"d", 20, 80},  // Evicts "b" (40), the least recently used.
+	}
+	for _, s := range steps {
+		cache.Put(s.key, s.val)
+		if w := cache.TotalWeight(); w != s.want {
+			t.Fatalf("After Put(%q, %d): TotalWeight() = %d; want %d", s.key, s.val, w, s.want)
+		}
+	}
+}
+
+// TestLRUCache_TotalWeightUnweighted tests that every entry weighs 1 in a plain cache.
+func TestLRUCache_TotalWeightUnweighted(t *testing.T) {
+	cache := NewLRUCache[int, int](2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+
+	if w := cache.TotalWeight(); w != 2 {
+		t.Fatalf("TotalWeight() = %d; want %d", w, 2)
+	}
+}
+
+// TestLRUCache_EntryOverhead tests that the overhead estimate covers at least the key and value.
+func TestLRUCache_EntryOverhead(t *testing.T) {
+	small := NewLRUCache[int, int](1).EntryOverhead()
+	large := NewLRUCache[int, [64]byte](1).EntryOverhead()
+
+	if small < 16 {
+		t.Errorf("Expected overhead of at least 16 bytes for int keys and values, got %d", small)
+	}
+	if want := 64 - int64(unsafe.Sizeof(int(0))); large-small != want {
+		t.Errorf("Expected a [64]byte value to add %d bytes over an int, got %d", want, large-small)
+	}
+}
+
+// TestWeightedLRUCache_TryPut tests that TryPut rejects a new entry that would exceed the budget.
+func TestWeightedLRUCache_TryPut(t *testing.T) {
+	cache := NewWeightedLRUCache[string, string](10, func(_ string, v string) int64 { return int64(len(v)) })
+
+	if !cache.TryPut("a", "123456") {
+		t.Fatal("Expected TryPut to succeed within the budget")
+	}
+	if cache.TryPut("b", "12345") {
+		t.Fatal("Expected TryPut to fail when the entry exceeds the remaining budget")
+	}
+	if !cache.TryPut("c", "1234") {
+		t.Fatal("Expected TryPut to succeed for an entry that fits exactly")
+	}
+	if n := cache.TotalWeight(); n != 10 {
+		t.Fatalf("cache.TotalWeight() = %d; want %d", n, 10)
+	}
+}
+
+// TestWeightedLRUCache_Clear tests that a weighted cache can be cleared and releases all weight.
+func TestWeightedLRUCache_Clear(t *testing.T) {
+	cache := NewSizedLRUCache[string, blob](100)
+	cache.Put("a", make(blob, 40))
+	cache.Put("b", make(blob, 40))
+
+	cache.Clear()
+	if w := cache.TotalWeight(); w != 0 {
+		t.Fatalf("Expected total weight 0 after Clear, got %d", w)
+	}
+	cache.Put("c", make(blob, 90))
+	if w := cache.TotalWeight(); w != 90 {
+		t.Errorf("Expected total weight 90, got %d", w)
+	}
+}
+
+// TestWeightedLRUCache_EvictsSeveral tests that one costly Put evicts as many least recently used entries as needed.
+func TestWeightedLRUCache_EvictsSeveral(t *testing.T) {
+	cache := NewWeightedLRUCache[string, int](10, func(_ string, cost int) int64 { return int64(cost) })
+	var evicted []string
+	cache.SetOnEvict(func(key string, _ int) { evicted = append(evicted, key) })
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		cache.Put(key, 2)
+	}
+	cache.Put("big", 7) // 17 of 10: the four oldest must go to get back within budget.
+
+	if len(evicted) != 4 || evicted[0] != "a" || evicted[3] != "d" {
+		t.Fatalf("Expected [a b c d] to be evicted, got %v", evicted)
+	}
+	if w := cache.TotalWeight(); w != 9 {
+		t.Errorf("Expected total weight 9, got %d", w)
+	}
+}
+
+// TestWeightedLRUCache_Stats tests that Stats reports the budget and weight of a weighted cache.
+func TestWeightedLRUCache_Stats(t *testing.T) {
+	cache := NewWeightedLRUCache[string, string](10, func(_ string, v string) int64 { return int64(len(v)) })
+	cache.Put("a", "xx")
+	cache.Put("b", "xxx")
+
+	want := CacheStats{Budget: 10, Weight: 5, Length: 2, LoadFactor: 0.5}
+	if s := cache.Stats(); s != want {
+		t.Fatalf("Expected %+v, got %+v", want, s)
+	}
+}
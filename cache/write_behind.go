@@ -0,0 +1,158 @@
+// write_behind.go contains the implementation of the WriteBehindCache type, an
+// LRU cache that defers writes to a backing store. Writes land in memory first
+// and are flushed to the store periodically, on Close, or just before a dirty
+// entry would be evicted, so no unflushed write is ever silently dropped.
+// Deletes are deferred to the same flushes.
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// WriteBehindCache wraps an LRUCache and buffers writes to a backing store.
+// Reads are always served from the in-memory state, including entries that have
+// not been flushed yet, so callers observe their own writes immediately.
+type WriteBehindCache[K comparable, V any] struct {
+	lru     *LRUCache[K, V]    // In-memory state, the source of truth for reads.
+	write   func(key K, val V) // Writes a single entry to the backing store.
+	del     func(key K)        // Deletes a single entry from the backing store; may be nil.
+	dirty   map[K]struct{}     // Keys written since they were last flushed.
+	deleted map[K]struct{}     // Keys deleted since the last flush, if del is set.
+	mu      sync.Mutex         // Mutex to protect the dirty and deleted sets and serialize writes.
+	stop    chan struct{}      // Closed to stop the background flusher.
+	done    chan struct{}      // Closed once the background flusher has exited.
+	once    sync.Once          // Ensures Close runs only once.
+}
+
+// NewWriteBehindCache creates a new WriteBehindCache with the given capacity.
+// Dirty entries are passed to write every interval, and write is also invoked
+// synchronously for a dirty entry that is about to be evicted. write is called
+// with the cache's lock held and must not call back into the cache.
+func NewWriteBehindCache[K comparable, V any](capacity int, interval time.Duration, write func(key K, val V)) *WriteBehindCache[K, V] {
+	if interval <= 0 {
+		panic("cache: flush interval must be greater than zero")
+	}
+	if write == nil {
+		panic("cache: write function must not be nil")
+	}
+
+	c := &WriteBehindCache[K, V]{
+		lru:     NewLRUCache[K, V](capacity),
+		write:   write,
+		dirty:   make(map[K]struct{}),
+		deleted: make(map[K]struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	c.lru.onEvict = c.flushEvicted
+	go c.run(interval)
+	return c
+}
+
+// Get retrieves the value associated with the given key. Values that have been
+// Put but not yet flushed are returned as well.
+func (c *WriteBehindCache[K, V]) Get(key K) (V, bool) {
+	return c.lru.Get(key)
+}
+
+// Put stores the key-value pair in memory and marks it dirty. The value is
+// written to the backing store on the next flush or before it is evicted.
+func (c *WriteBehindCache[K, V]) Put(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Put(key, val)
+	c.dirty[key] = struct{}{}
+	delete(c.deleted, key) // The write supersedes the pending delete.
+}
+
+// SetDeleteFunc sets del to be called to remove a deleted key from the
+// backing store. Like writes, deletes are deferred to the next flush. Without
+// it, Delete only affects the cache and the store keeps the last value flushed
+// for the key. del is called with the cache's lock held and must not call back
+// into the cache. It should be set before the cache is used.
+func (c *WriteBehindCache[K, V]) SetDeleteFunc(del func(key K)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.del = del
+}
+
+// Delete removes key from memory and reports whether it was present. A write
+// to key that has not been flushed yet is discarded, and if a delete function
+// was set, the key is deleted from the backing store on the next flush, even
+// if it was no longer in memory.
+func (c *WriteBehindCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.dirty, key)
+	if c.del != nil {
+		c.deleted[key] = struct{}{}
+	}
+	return c.lru.Delete(key)
+}
+
+// Len returns the number of entries in memory, flushed or not.
+func (c *WriteBehindCache[K, V]) Len() int {
+	return c.lru.Len()
+}
+
+// Flush deletes the deleted keys from the backing store and writes all dirty
+// entries to it.
+func (c *WriteBehindCache[K, V]) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.mu.Lock()
+	defer c.lru.mu.Unlock()
+
+	for key := range c.deleted {
+		c.del(key)
+		delete(c.deleted, key)
+	}
+	for key := range c.dirty {
+		if i, ok := c.lru.dict[key]; ok {
+			c.write(key, c.lru.entries[i].value)
+		}
+		delete(c.dirty, key)
+	}
+}
+
+// Close stops the background flusher and writes any remaining dirty entries.
+// It is safe to call Close more than once.
+func (c *WriteBehindCache[K, V]) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+		<-c.done
+		c.Flush()
+	})
+}
+
+// flushEvicted is installed as the LRUCache eviction hook. It runs while Put
+// holds c.mu and writes the entry out if it has not been flushed yet.
+func (c *WriteBehindCache[K, V]) flushEvicted(key K, val V) {
+	if _, ok := c.dirty[key]; ok {
+		c.write(key, val)
+		delete(c.dirty, key)
+	}
+}
+
+// run flushes dirty entries every interval until the cache is closed.
+func (c *WriteBehindCache[K, V]) run(interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.Flush()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// store is a thread-safe fake backing store used by the write-behind tests.
+type store struct {
+	mu     sync.Mutex
+	writes map[string]int
+}
+
+func newStore() *store {
+	return &store{writes: make(map[string]int)}
+}
+
+func (s *store) write(key string, val int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes[key] = val
+}
+
+func (s *store) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.writes, key)
+}
+
+func (s *store) get(key string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.writes[key]
+	return v, ok
+}
+
+// TestWriteBehindCache_ReadYourWrites tests that a Get right after a Put sees the unflushed value.
+func TestWriteBehindCache_ReadYourWrites(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](2, time.Hour, s.write)
+	defer cache.Close()
+
+	cache.Put("key1", 1)
+	if v, ok := cache.Get("key1"); !ok || v != 1 {
+		t.Fatalf("cache.Get(\"key1\") = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	if _, ok := s.get("key1"); ok {
+		t.Fatal("Expected \"key1\" not to be flushed yet")
+	}
+
+	cache.Put("key1", 2)
+	if v, ok := cache.Get("key1"); !ok || v != 2 {
+		t.Fatalf("cache.Get(\"key1\") after update = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestWriteBehindCache_FlushOnEviction tests that a dirty entry is written out before it is evicted.
+func TestWriteBehindCache_FlushOnEviction(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](2, time.Hour, s.write)
+	defer cache.Close()
+
+	cache.Put("key1", 1)
+	cache.Put("key2", 2)
+	cache.Put("key3", 3) // Evicts "key1"
+
+	if v, ok := s.get("key1"); !ok || v != 1 {
+		t.Fatalf("store[\"key1\"] = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+	if _, ok := s.get("key2"); ok {
+		t.Fatal("Expected \"key2\" not to be flushed yet")
+	}
+}
+
+// TestWriteBehindCache_Flush tests that Flush and Close write all dirty entries.
+func TestWriteBehindCache_Flush(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](4, time.Hour, s.write)
+
+	cache.Put("key1", 1)
+	cache.Flush()
+	if v, ok := s.get("key1"); !ok || v != 1 {
+		t.Fatalf("store[\"key1\"] = %v, %v; want %v, %v", v, ok, 1, true)
+	}
+
+	cache.Put("key2", 2)
+	cache.Close()
+	if v, ok := s.get("key2"); !ok || v != 2 {
+		t.Fatalf("store[\"key2\"] = %v, %v; want %v, %v", v, ok, 2, true)
+	}
+}
+
+// TestWriteBehindCache_BackgroundFlush tests that dirty entries are flushed periodically.
+func TestWriteBehindCache_BackgroundFlush(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](4, 10*time.Millisecond, s.write)
+	defer cache.Close()
+
+	cache.Put("key1", 1)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := s.get("key1"); ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for background flush")
+}
+
+// TestWriteBehindCache_Delete tests that deleting a dirty entry discards its unflushed write.
+func TestWriteBehindCache_Delete(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](2, time.Hour, s.write)
+
+	cache.Put("key1", 1)
+	if !cache.Delete("key1") || cache.Delete("key1") {
+		t.Fatal("Expected deleting \"key1\" to succeed once")
+	}
+	cache.Close()
+	if v, ok := s.get("key1"); ok {
+		t.Fatalf("Expected the deleted write not to reach the store, got %v", v)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Errorf("Expected an empty cache, got length %d", n)
+	}
+}
+
+// TestWriteBehindCache_DeleteFunc tests that deletes reach the store on the next flush unless a later Put supersedes them.
+func TestWriteBehindCache_DeleteFunc(t *testing.T) {
+	s := newStore()
+	cache := NewWriteBehindCache[string, int](2, time.Hour, s.write)
+	cache.SetDeleteFunc(s.delete)
+
+	cache.Put("key1", 1)
+	cache.Put("key2", 2)
+	cache.Flush()
+	cache.Delete("key1")
+	cache.Delete("key2")
+	cache.Put("key2", 3)
+	if _, ok := s.get("key1"); !ok {
+		t.Fatal("Expected the delete to wait for the next flush")
+	}
+
+	cache.Close()
+	if v, ok := s.get("key1"); ok {
+		t.Errorf("Expected \"key1\" to be deleted from the store, got %v", v)
+	}
+	if v, ok := s.get("key2"); !ok || v != 3 {
+		t.Errorf("store[\"key2\"] = %v, %v; want %v, %v", v, ok, 3, true)
+	}
+}
@@ -0,0 +1,206 @@
+// write_through.go contains the implementation of the WriteThroughCache type,
+// a decorator that keeps any Cache in front of a RemoteStore: reads fall back
+// to the store and writes are persisted to it, either synchronously or through
+// a bounded queue drained by a background goroutine. TieredCache is a
+// WriteThroughCache over an LRUCache.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCacheClosed is returned by WriteThroughCache writes after Close.
+var ErrCacheClosed = errors.New("cache: closed")
+
+// storeOp is a write queued for the store in write-behind mode.
+type storeOp[K comparable, V any] struct {
+	key K
+	val V
+	del bool // Whether the key is deleted rather than set.
+}
+
+// WriteThroughCache decorates a Cache with a backing RemoteStore. Get serves
+// hits from the cache and otherwise reads through to the store, caching what
+// it finds. In the default write-through mode, Put and Delete update the store
+// before returning. In write-behind mode, created by
+// NewWriteThroughCacheAsync, they update the cache and queue the store write,
+// which a background goroutine performs in order; until then, Get answers
+// from the queued write rather than the store, even if the cache has evicted
+// it. A value read from the store is not cached if the key was written while
+// it was being read. WriteThroughCache is safe for concurrent use if the cache
+// and the store are.
+type WriteThroughCache[K comparable, V any] struct {
+	cache   Cache[K, V]            // The decorated cache.
+	store   RemoteStore[K, V]      // The backing store.
+	guard   keyGuard[K, V]         // Keeps store reads from undoing concurrent writes in the cache.
+	queue   chan storeOp[K, V]     // Pending store writes in write-behind mode; nil in write-through mode.
+	slots   chan struct{}          // Holds a token per queued write not yet flushed, bounding the queue.
+	onError func(key K, err error) // Receives errors of queued writes; may be nil.
+	done    chan struct{}          // Closed once the flusher has drained the queue and exited.
+	closed  bool                   // Whether Close was called; guarded by mu.
+	mu      sync.RWMutex           // Held for reading by writers and for writing by Close.
+}
+
+// NewWriteThroughCache creates a WriteThroughCache in write-through mode.
+func NewWriteThroughCache[K comparable, V any](cache Cache[K, V], store RemoteStore[K, V]) *WriteThroughCache[K, V] {
+	if cache == nil || store == nil {
+		panic("cache: cache and store must not be nil")
+	}
+
+	return &WriteThroughCache[K, V]{cache: cache, store: store}
+}
+
+// NewWriteThroughCacheAsync creates a WriteThroughCache in write-behind mode.
+// Up to queueSize writes, including the one being flushed, wait for the store;
+// when the queue is full, Put and Delete block until there is room or their
+// context is done. Queued writes use
+// a background context, and their errors are passed to onError, if not nil,
+// on the flusher goroutine. Close flushes the queue before returning.
+func NewWriteThroughCacheAsync[K comparable, V any](cache Cache[K, V], store RemoteStore[K, V],
+	queueSize int, onError func(key K, err error)) *WriteThroughCache[K, V] {
+	if queueSize <= 0 {
+		panic("cache: queue size must be greater than zero")
+	}
+
+	c := NewWriteThroughCache(cache, store)
+	c.queue = make(chan storeOp[K, V], queueSize)
+	c.slots = make(chan struct{}, queueSize)
+	c.onError = onError
+	c.done = make(chan struct{})
+	go c.flush()
+	return c
+}
+
+// Get retrieves the value for key from the cache or, on a miss, from the
+// latest queued write of key or else the store, caching a value found in the
+// store unless key was written meanwhile. It returns the store's error, if
+// any.
+func (c *WriteThroughCache[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	if val, ok := c.cache.Get(key); ok {
+		return val, true, nil
+	}
+
+	gen, val, found, queued := c.guard.begin(key)
+	if queued {
+		return val, found, nil
+	}
+	val, ok, err := c.store.Get(ctx, key)
+	c.guard.fill(key, gen, func() {
+		if err == nil && ok {
+			c.cache.Put(key, val)
+		}
+	})
+	if err != nil || !ok {
+		return val, false, err
+	}
+	return val, true, nil
+}
+
+// Put stores the key-value pair. In write-through mode it writes to the store
+// first and then to the cache; if the store write fails, key is removed from
+// the cache instead and the error returned. In write-behind mode it writes to
+// the cache and queues the store write; if ctx is done while the queue is
+// full, nothing is written and ctx's error is returned.
+func (c *WriteThroughCache[K, V]) Put(ctx context.Context, key K, val V) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	if c.queue != nil {
+		return c.enqueue(ctx, storeOp[K, V]{key: key, val: val})
+	}
+	err := c.store.Set(ctx, key, val)
+	c.guard.write(key, func() {
+		if err != nil {
+			c.cache.Delete(key)
+		} else {
+			c.cache.Put(key, val)
+		}
+	})
+	return err
+}
+
+// Delete removes key from the store and the cache. In write-through mode the
+// cache entry is removed even if the store delete fails. In write-behind mode
+// the key is removed from the cache and the store delete queued, as for Put.
+func (c *WriteThroughCache[K, V]) Delete(ctx context.Context, key K) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	if c.queue != nil {
+		return c.enqueue(ctx, storeOp[K, V]{key: key, del: true})
+	}
+	err := c.store.Delete(ctx, key)
+	c.guard.write(key, func() { c.cache.Delete(key) })
+	return err
+}
+
+// Close flushes the writes still queued in write-behind mode, then closes the
+// decorated cache. Later writes return ErrCacheClosed. It is safe to call Close
+// more than once.
+func (c *WriteThroughCache[K, V]) Close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	if c.queue != nil {
+		close(c.queue)
+	}
+	c.mu.Unlock()
+
+	if c.done != nil {
+		<-c.done
+	}
+	c.cache.Close()
+}
+
+// enqueue waits for room in the queue until ctx is done, then applies op to
+// the cache and queues it for the flusher. The caller must hold c.mu for
+// reading, so Close cannot close the queue meanwhile.
+func (c *WriteThroughCache[K, V]) enqueue(ctx context.Context, op storeOp[K, V]) error {
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	c.guard.enqueue(op.key, op.val, op.del, func() {
+		if op.del {
+			c.cache.Delete(op.key)
+		} else {
+			c.cache.Put(op.key, op.val)
+		}
+		c.queue <- op // Never blocks: the slot taken above reserves room.
+	})
+	return nil
+}
+
+// flush performs queued writes in order until the queue is closed and drained.
+func (c *WriteThroughCache[K, V]) flush() {
+	defer close(c.done)
+
+	ctx := context.Background()
+	for op := range c.queue {
+		var err error
+		if op.del {
+			err = c.store.Delete(ctx, op.key)
+		} else {
+			err = c.store.Set(ctx, op.key, op.val)
+		}
+		if err != nil && c.onError != nil {
+			c.onError(op.key, err)
+		}
+		c.guard.flushed(op.key)
+		<-c.slots
+	}
+}
@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is an in-memory RemoteStore that can be made to fail or to block.
+type mapStore struct {
+	mu    sync.Mutex
+	data  map[string]int
+	err   error
+	gate  chan struct{} // If not nil, Set and Delete wait for it to be closed.
+	order []string      // Keys of successful Sets and Deletes, in order.
+
+	afterGet func() // If not nil, called by Get after reading, without the lock held.
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: make(map[string]int)}
+}
+
+func (s *mapStore) Get(_ context.Context, key string) (int, bool, error) {
+	s.mu.Lock()
+	v, ok := s.data[key]
+	err := s.err
+	s.mu.Unlock()
+	if s.afterGet != nil {
+		s.afterGet()
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return v, ok, nil
+}
+
+func (s *mapStore) Set(_ context.Context, key string, val int) error {
+	s.wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = val
+	s.order = append(s.order, key)
+	return nil
+}
+
+func (s *mapStore) Delete(_ context.Context, key string) error {
+	s.wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.data, key)
+	s.order = append(s.order, "-"+key)
+	return nil
+}
+
+func (s *mapStore) wait() {
+	if s.gate != nil {
+		<-s.gate
+	}
+}
+
+// TestWriteThroughCache_ReadThrough tests that a miss is read from the store and cached.
+func TestWriteThroughCache_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	store.data["a"] = 1
+	lru := NewLRUCache[string, int](2)
+	cache := NewWriteThroughCache[string, int](lru, store)
+	defer cache.Close()
+
+	if v, ok, err := cache.Get(ctx, "a"); err != nil || !ok || v != 1 {
+		t.Fatalf("cache.Get(a) = %d, %v, %v; want 1, true, nil", v, ok, err)
+	}
+	if !lru.Contains("a") {
+		t.Error("Expected a to be cached after the read-through")
+	}
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("cache.Get(missing) = _, %v, %v; want false, nil", ok, err)
+	}
+}
+
+// TestWriteThroughCache_Sync tests that writes reach the store before returning and a failed write drops the cached key.
+func TestWriteThroughCache_Sync(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	lru := NewLRUCache[string, int](2)
+	cache := NewWriteThroughCache[string, int](lru, store)
+
+	if err := cache.Put(ctx, "a", 1); err != nil || store.data["a"] != 1 || !lru.Contains("a") {
+		t.Fatalf("Expected a to be written to both, got err %v and store %v", err, store.data)
+	}
+	if err := cache.Delete(ctx, "a"); err != nil || len(store.data) != 0 || lru.Contains("a") {
+		t.Fatalf("Expected a to be deleted from both, got err %v and store %v", err, store.data)
+	}
+
+	lru.Put("b", 1)
+	store.err = errors.New("unavailable")
+	if err := cache.Put(ctx, "b", 2); !errors.Is(err, store.err) {
+		t.Fatalf("cache.Put() error = %v; want %v", err, store.err)
+	}
+	if lru.Contains("b") {
+		t.Error("Expected a failed write to drop the cached key")
+	}
+
+	cache.Close()
+	if err := cache.Put(ctx, "c", 3); !errors.Is(err, ErrCacheClosed) {
+		t.Errorf("cache.Put() after Close error = %v; want %v", err, ErrCacheClosed)
+	}
+}
+
+// TestWriteThroughCache_Async tests that queued writes are applied in order and flushed by Close.
+func TestWriteThroughCache_Async(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	store.gate = make(chan struct{})
+	lru := NewLRUCache[string, int](4)
+	cache := NewWriteThroughCacheAsync[string, int](lru, store, 4, nil)
+
+	cache.Put(ctx, "a", 1)
+	cache.Put(ctx, "b", 2)
+	cache.Delete(ctx, "a")
+	if v, ok, _ := cache.Get(ctx, "b"); !ok || v != 2 {
+		t.Fatalf("Expected b to be served before it is flushed, got %d, %v", v, ok)
+	}
+
+	close(store.gate)
+	cache.Close()
+	if want := []string{"a", "b", "-a"}; !reflect.DeepEqual(store.order, want) {
+		t.Fatalf("Expected store writes %v, got %v", want, store.order)
+	}
+	if _, ok := store.data["a"]; ok || store.data["b"] != 2 {
+		t.Fatalf("Expected the store to hold only b, got %v", store.data)
+	}
+}
+
+// TestWriteThroughCache_AsyncFull tests that a write blocked on a full queue gives up with its context and is not cached.
+func TestWriteThroughCache_AsyncFull(t *testing.T) {
+	store := newMapStore()
+	store.gate = make(chan struct{})
+	lru := NewLRUCache[string, int](4)
+	var failed []string
+	cache := NewWriteThroughCacheAsync[string, int](lru, store, 2, func(key string, err error) {
+		failed = append(failed, key)
+	})
+
+	cache.Put(context.Background(), "a", 1) // Taken by the flusher, which blocks on the gate.
+	cache.Put(context.Background(), "b", 2) // Fills the queue.
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := cache.Put(ctx, "c", 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("cache.Put() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if lru.Contains("c") {
+		t.Error("Expected a write that was not queued to be dropped from the cache")
+	}
+
+	store.mu.Lock()
+	store.err = errors.New("unavailable")
+	store.mu.Unlock()
+	close(store.gate)
+	cache.Close()
+	if len(failed) != 2 {
+		t.Errorf("Expected both queued writes to be reported as failed, got %v", failed)
+	}
+}
+
+// TestWriteThroughCache_GetRacingWrites tests that a value read from the store is not cached over a write made meanwhile.
+func TestWriteThroughCache_GetRacingWrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(c *WriteThroughCache[string, int]) error
+		want  int
+		found bool
+	}{
+		{"Delete", func(c *WriteThroughCache[string, int]) error { return c.Delete(context.Background(), "a") }, 0, false},
+		{"Put", func(c *WriteThroughCache[string, int]) error { return c.Put(context.Background(), "a", 2) }, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMapStore()
+			store.data["a"] = 1
+			lru := NewLRUCache[string, int](2)
+			cache := NewWriteThroughCache[string, int](lru, store)
+			defer cache.Close()
+
+			store.afterGet = func() {
+				store.afterGet = nil
+				if err := tt.write(cache); err != nil {
+					t.Fatalf("Unexpected write error: %v", err)
+				}
+			}
+			if v, ok, _ := cache.Get(context.Background(), "a"); !ok || v != 1 {
+				t.Fatalf("cache.Get(a) = %d, %v; want 1, true", v, ok)
+			}
+			if v, ok := lru.Get("a"); ok != tt.found || v != tt.want {
+				t.Errorf("Cached a = %d, %v; want %d, %v", v, ok, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+// TestWriteThroughCache_AsyncReadQueued tests that a cache miss on a key with a queued write is answered from the write rather than the stale store.
+func TestWriteThroughCache_AsyncReadQueued(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	store.data["a"], store.data["b"] = 1, 1
+	store.gate = make(chan struct{})
+	lru := NewLRUCache[string, int](2)
+	cache := NewWriteThroughCacheAsync[string, int](lru, store, 4, nil)
+
+	cache.Delete(ctx, "a")
+	cache.Put(ctx, "b", 2)
+	lru.Delete("b") // As if evicted before its write is flushed.
+
+	if _, ok, err := cache.Get(ctx, "a"); ok || err != nil {
+		t.Errorf("cache.Get(a) = _, %v, %v; want false, nil", ok, err)
+	}
+	if v, ok, err := cache.Get(ctx, "b"); !ok || v != 2 || err != nil {
+		t.Errorf("cache.Get(b) = %d, %v, %v; want 2, true, nil", v, ok, err)
+	}
+	if lru.Contains("a") {
+		t.Error("Expected the stale store value of a not to be cached")
+	}
+
+	close(store.gate)
+	cache.Close()
+	if v, ok, err := cache.Get(ctx, "b"); !ok || v != 2 || err != nil {
+		t.Errorf("cache.Get(b) after flushing = %d, %v, %v; want 2, true, nil", v, ok, err)
+	}
+	if len(cache.guard.keys) != 0 {
+		t.Errorf("Expected no keys tracked once flushed, got %d", len(cache.guard.keys))
+	}
+}
@@ -0,0 +1,73 @@
+// Package random centralizes the randomness used by this module, such as
+// jittered TTLs and retry backoff. Features draw from a Source rather than
+// the global math/rand functions so that tests can inject a seeded Source and
+// get reproducible results.
+package random
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Source is a source of pseudo-random numbers. Implementations must be safe
+// for concurrent use.
+type Source interface {
+	// Int63n returns a non-negative pseudo-random number in [0, n). It panics
+	// if n <= 0.
+	Int63n(n int64) int64
+}
+
+// lockedRand is a Source backed by a math/rand generator guarded by a mutex,
+// as *rand.Rand is not safe for concurrent use on its own.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// New returns a Source seeded with seed. Two sources with the same seed
+// produce the same sequence, which is what tests rely on.
+func New(seed int64) Source {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+// Int63n implements Source.
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.r.Int63n(n)
+}
+
+// Default is the Source used unless a test substitutes its own.
+var Default = New(time.Now().UnixNano())
+
+// Jitter returns d spread uniformly over [d-d*frac, d+d*frac], so that entries
+// written together do not all expire together. frac is clamped to [0, 1].
+func Jitter(src Source, d time.Duration, frac float64) time.Duration {
+	if frac > 1 {
+		frac = 1
+	}
+	spread := int64(float64(d) * frac)
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(src.Int63n(2*spread+1))
+}
+
+// Backoff returns the delay before retry number attempt, counting from zero.
+// The delay doubles with each attempt up to max, and the "full jitter"
+// strategy then picks a uniformly random delay in [0, that ceiling].
+func Backoff(src Source, attempt int, base, max time.Duration) time.Duration {
+	ceiling := base
+	for i := 0; i < attempt && ceiling < max; i++ {
+		ceiling *= 2
+	}
+	if ceiling > max {
+		ceiling = max
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(src.Int63n(int64(ceiling) + 1))
+}
@@ -0,0 +1,49 @@
+package random
+
+import (
+	"testing"
+	"time"
+)
+
+// TestJitter_Reproducible tests that jittered durations repeat for the same seed and stay in range.
+func TestJitter_Reproducible(t *testing.T) {
+	a, b := New(42), New(42)
+	varied := false
+	for i := 0; i < 100; i++ {
+		x := Jitter(a, time.Minute, 0.1)
+		if y := Jitter(b, time.Minute, 0.1); x != y {
+			t.Fatalf("Expected identical jitter for the same seed, got %v and %v", x, y)
+		}
+		if x < 54*time.Second || x > 66*time.Second {
+			t.Fatalf("Expected jitter within 10%% of a minute, got %v", x)
+		}
+		varied = varied || x != time.Minute
+	}
+	if !varied {
+		t.Fatal("Expected jitter to vary the duration")
+	}
+	if d := Jitter(a, time.Minute, 0); d != time.Minute {
+		t.Fatalf("Jitter(d, 0) = %v; want %v", d, time.Minute)
+	}
+}
+
+// TestBackoff_Reproducible tests that backoff delays repeat for the same seed and respect the growing ceiling.
+func TestBackoff_Reproducible(t *testing.T) {
+	a, b := New(7), New(7)
+	ceilings := []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		50 * time.Millisecond,
+		50 * time.Millisecond,
+	}
+	for attempt, ceiling := range ceilings {
+		x := Backoff(a, attempt, 10*time.Millisecond, 50*time.Millisecond)
+		if y := Backoff(b, attempt, 10*time.Millisecond, 50*time.Millisecond); x != y {
+			t.Fatalf("Expected identical backoff for the same seed, got %v and %v", x, y)
+		}
+		if x < 0 || x > ceiling {
+			t.Fatalf("Expected backoff for attempt %d in [0, %v], got %v", attempt, ceiling, x)
+		}
+	}
+}
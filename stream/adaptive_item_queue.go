@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AdaptiveItemQueue is a queue that is lossless under normal load and lossy under bursts. Items are
+// buffered in order up to maxBacklog; when a new item arrives while the backlog is full, the queue
+// collapses: the backlog is discarded and only the new item is kept, as in LatestItemQueue. This
+// preserves every item in the common case while bounding memory during a flood.
+type AdaptiveItemQueue[T any] struct {
+	collapses uint64 // Number of collapse events; accessed atomically and kept first for 64-bit alignment.
+
+	channel chan T        // Buffered channel holding up to maxBacklog items.
+	closed  chan struct{} // Indicator for closing the consume channel
+	mu      sync.Mutex    // Serializes producers and Close so that a collapse is atomic.
+}
+
+// NewAdaptiveItemQueue creates a new AdaptiveItemQueue that buffers up to maxBacklog items before
+// collapsing to the latest one.
+func NewAdaptiveItemQueue[T any](maxBacklog int) *AdaptiveItemQueue[T] {
+	if maxBacklog <= 0 {
+		panic("stream: maxBacklog must be greater than zero")
+	}
+	return &AdaptiveItemQueue[T]{
+		channel: make(chan T, maxBacklog),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Produce enqueues an item without blocking. If the backlog is full, it is discarded and the queue
+// keeps only item, counting one collapse.
+func (q *AdaptiveItemQueue[T]) Produce(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-q.closed: // Check if the queue is closed to prevent sending on closed channel
+		return
+	default:
+	}
+
+	select {
+	case q.channel <- item:
+		return
+	default:
+	}
+
+	// The backlog is full, collapse to the latest item.
+	atomic.AddUint64(&q.collapses, 1)
+	for drained := false; !drained; {
+		select {
+		case <-q.channel:
+		default:
+			drained = true
+		}
+	}
+	q.channel <- item // Producers are serialized, so the channel has room.
+}
+
+// ConsumeChannel provides access to the underlying channel for consuming items in order.
+func (q *AdaptiveItemQueue[T]) ConsumeChannel() <-chan T {
+	return q.channel
+}
+
+// Collapses returns the number of times the backlog was discarded because it was full.
+func (q *AdaptiveItemQueue[T]) Collapses() uint64 {
+	return atomic.LoadUint64(&q.collapses)
+}
+
+// Close closes the consume channel after any buffered items, ensuring no more items can be sent.
+// It is safe to call Close more than once.
+func (q *AdaptiveItemQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-q.closed: // Prevent closing more than once
+		return
+	default:
+		close(q.closed)
+		close(q.channel)
+	}
+}
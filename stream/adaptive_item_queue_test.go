@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAdaptiveItemQueue_LightLoad tests that no items are lost while the backlog stays within bounds.
+func TestAdaptiveItemQueue_LightLoad(t *testing.T) {
+	q := NewAdaptiveItemQueue[int](4)
+
+	var got []int
+	for i := 0; i < 10; i++ {
+		q.Produce(i)
+		if i%2 == 1 { // The consumer keeps up, two items at a time.
+			got = append(got, <-q.ConsumeChannel(), <-q.ConsumeChannel())
+		}
+	}
+
+	want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if n := q.Collapses(); n != 0 {
+		t.Errorf("Expected 0 collapses, got %d", n)
+	}
+}
+
+// TestAdaptiveItemQueue_Flood tests that a flood collapses to the latest item with an accurate count.
+func TestAdaptiveItemQueue_Flood(t *testing.T) {
+	q := NewAdaptiveItemQueue[int](3)
+
+	for i := 1; i <= 3; i++ {
+		q.Produce(i) // Fills the backlog.
+	}
+	q.Produce(4) // Collapse: only 4 is kept.
+	q.Produce(5)
+	q.Produce(6)
+	q.Produce(7) // Collapse: only 7 is kept.
+	q.Close()
+
+	if got := collect(q.ConsumeChannel()); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("Expected [7], got %v", got)
+	}
+	if n := q.Collapses(); n != 2 {
+		t.Errorf("Expected 2 collapses, got %d", n)
+	}
+}
+
+// TestAdaptiveItemQueue_Close tests that buffered items are still delivered after Close and later produces are ignored.
+func TestAdaptiveItemQueue_Close(t *testing.T) {
+	q := NewAdaptiveItemQueue[int](2)
+	q.Produce(1)
+	q.Close()
+	q.Close()    // Closing twice is a no-op.
+	q.Produce(2) // Ignored after Close.
+
+	if got := collect(q.ConsumeChannel()); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Expected [1], got %v", got)
+	}
+}
@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier is a WaitGroup-like counter for coordinating a dynamic set of goroutines. Unlike
+// sync.WaitGroup, Wait accepts a context and can be abandoned early, and the number of outstanding
+// tasks can be queried. Add may be called at any time, including while another goroutine waits.
+type Barrier struct {
+	mu    sync.Mutex    // Mutex to protect count and zero.
+	count int           // Number of outstanding tasks.
+	zero  chan struct{} // Closed whenever count is zero; replaced when count leaves zero.
+}
+
+// NewBarrier creates a new Barrier with no outstanding tasks.
+func NewBarrier() *Barrier {
+	zero := make(chan struct{})
+	close(zero)
+	return &Barrier{zero: zero}
+}
+
+// Add adds n, which may be negative, to the number of outstanding tasks. It panics if the count
+// would become negative.
+func (b *Barrier) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.count+n < 0 {
+		panic("stream: negative barrier count")
+	}
+	wasZero := b.count == 0
+	b.count += n
+
+	switch {
+	case wasZero && b.count > 0:
+		b.zero = make(chan struct{})
+	case !wasZero && b.count == 0:
+		close(b.zero) // Release all waiters.
+	}
+}
+
+// Done decrements the number of outstanding tasks by one.
+func (b *Barrier) Done() {
+	b.Add(-1)
+}
+
+// Remaining returns the number of outstanding tasks.
+func (b *Barrier) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.count
+}
+
+// Wait blocks until the number of outstanding tasks reaches zero, returning nil, or until ctx is
+// done, returning ctx.Err(). Cancelling Wait does not affect the count.
+func (b *Barrier) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	zero := b.zero
+	b.mu.Unlock()
+
+	select {
+	case <-zero:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
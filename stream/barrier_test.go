@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestBarrier_WaitReachesZero tests that Wait returns nil once all tasks are done.
+func TestBarrier_WaitReachesZero(t *testing.T) {
+	b := NewBarrier()
+	b.Add(3)
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			b.Done()
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if n := b.Remaining(); n != 0 {
+		t.Fatalf("Expected 0 remaining, got %d", n)
+	}
+}
+
+// TestBarrier_WaitEmpty tests that Wait returns immediately when there are no tasks.
+func TestBarrier_WaitEmpty(t *testing.T) {
+	if err := NewBarrier().Wait(context.Background()); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+}
+
+// TestBarrier_WaitCancel tests that Wait returns ctx.Err() on cancellation while the count is kept.
+func TestBarrier_WaitCancel(t *testing.T) {
+	b := NewBarrier()
+	b.Add(2)
+	b.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if n := b.Remaining(); n != 1 {
+		t.Fatalf("Expected 1 remaining, got %d", n)
+	}
+
+	b.Done()
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected nil after the last Done, got %v", err)
+	}
+}
+
+// TestBarrier_DynamicAdd tests that tasks added while waiting are waited for as well.
+func TestBarrier_DynamicAdd(t *testing.T) {
+	b := NewBarrier()
+	b.Add(1)
+
+	go func() {
+		b.Add(1) // Spawn a follow-up task before finishing.
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			b.Done()
+		}()
+		b.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if n := b.Remaining(); n != 0 {
+		t.Fatalf("Expected 0 remaining, got %d", n)
+	}
+}
+
+// TestBarrier_NegativePanics tests that driving the count below zero panics.
+func TestBarrier_NegativePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic on negative count")
+		}
+	}()
+	NewBarrier().Done()
+}
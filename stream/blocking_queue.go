@@ -0,0 +1,72 @@
+package stream
+
+import "context"
+
+// BlockingQueue is a bounded FIFO queue whose Put blocks while the queue is full and whose Take blocks
+// while it is empty. Both can be abandoned through a context. It is safe for concurrent use by multiple
+// goroutines.
+type BlockingQueue[T any] struct {
+	items chan T // Buffered channel holding the queued items.
+}
+
+// NewBlockingQueue creates a new BlockingQueue holding at most capacity items.
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	if capacity <= 0 {
+		panic("stream: capacity must be greater than zero")
+	}
+	return &BlockingQueue[T]{
+		items: make(chan T, capacity),
+	}
+}
+
+// Len returns the number of items currently queued.
+func (q *BlockingQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Put adds item to the back of the queue, blocking while the queue is full. It returns ctx.Err() if ctx
+// is cancelled first, in which case item is not queued.
+func (q *BlockingQueue[T]) Put(ctx context.Context, item T) error {
+	select {
+	case q.items <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Take removes and returns the item at the front of the queue, blocking while the queue is empty. It
+// returns ctx.Err() if ctx is cancelled first.
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// TakeBatch removes up to max items from the front of the queue. It blocks until at least one item is
+// available, then takes whatever else is already queued without waiting for more, so consumers can
+// amortize per-batch work. It returns ctx.Err() if ctx is cancelled before the first item arrives.
+func (q *BlockingQueue[T]) TakeBatch(ctx context.Context, max int) ([]T, error) {
+	if max <= 0 {
+		panic("stream: max must be greater than zero")
+	}
+	first, err := q.Take(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := []T{first}
+	for len(batch) < max {
+		select {
+		case item := <-q.items:
+			batch = append(batch, item)
+		default:
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestBlockingQueue_PutTake tests that items are taken in FIFO order.
+func TestBlockingQueue_PutTake(t *testing.T) {
+	q := NewBlockingQueue[int](3)
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		if err := q.Put(ctx, i); err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+	}
+	for want := 1; want <= 3; want++ {
+		if got, err := q.Take(ctx); err != nil || got != want {
+			t.Fatalf("q.Take() = %d, %v; want %d, nil", got, err, want)
+		}
+	}
+}
+
+// TestBlockingQueue_PutCancel tests that Put on a full queue returns ctx.Err() on cancellation.
+func TestBlockingQueue_PutCancel(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	q.Put(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Put(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if n := q.Len(); n != 1 {
+		t.Fatalf("Expected 1 item, got %d", n)
+	}
+}
+
+// TestBlockingQueue_TakeBatchSingle tests that TakeBatch returns a single item without waiting for more.
+func TestBlockingQueue_TakeBatchSingle(t *testing.T) {
+	q := NewBlockingQueue[int](10)
+	q.Put(context.Background(), 1)
+
+	batch, err := q.TakeBatch(context.Background(), 5)
+	if err != nil || len(batch) != 1 || batch[0] != 1 {
+		t.Fatalf("q.TakeBatch() = %v, %v; want [1], nil", batch, err)
+	}
+}
+
+// TestBlockingQueue_TakeBatchFull tests that TakeBatch drains at most max queued items.
+func TestBlockingQueue_TakeBatchFull(t *testing.T) {
+	q := NewBlockingQueue[int](10)
+	for i := 0; i < 8; i++ {
+		q.Put(context.Background(), i)
+	}
+
+	batch, err := q.TakeBatch(context.Background(), 5)
+	if err != nil || len(batch) != 5 {
+		t.Fatalf("q.TakeBatch() = %v, %v; want 5 items", batch, err)
+	}
+	for i, v := range batch {
+		if v != i {
+			t.Fatalf("Expected item %d at position %d, got %d", i, i, v)
+		}
+	}
+	if n := q.Len(); n != 3 {
+		t.Fatalf("Expected 3 items left, got %d", n)
+	}
+}
+
+// TestBlockingQueue_TakeBatchWaits tests that TakeBatch blocks until the first item arrives.
+func TestBlockingQueue_TakeBatchWaits(t *testing.T) {
+	q := NewBlockingQueue[int](10)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Put(context.Background(), 42)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	batch, err := q.TakeBatch(ctx, 5)
+	if err != nil || len(batch) != 1 || batch[0] != 42 {
+		t.Fatalf("q.TakeBatch() = %v, %v; want [42], nil", batch, err)
+	}
+}
+
+// TestBlockingQueue_TakeBatchCancel tests that TakeBatch on an empty queue returns ctx.Err() on cancellation.
+func TestBlockingQueue_TakeBatchCancel(t *testing.T) {
+	q := NewBlockingQueue[int](10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if batch, err := q.TakeBatch(ctx, 5); !errors.Is(err, context.Canceled) || batch != nil {
+		t.Fatalf("q.TakeBatch() = %v, %v; want nil, %v", batch, err, context.Canceled)
+	}
+}
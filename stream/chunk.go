@@ -0,0 +1,46 @@
+package stream
+
+// Chunk groups items from in into slices of exactly size items, in order. When
+// in is closed, any remaining items are emitted as a final, smaller chunk and
+// the returned channel is closed. Chunking is purely count driven: a partial
+// chunk is held until it fills up or in is closed.
+func Chunk[T any](in <-chan T, size int) <-chan []T {
+	if size <= 0 {
+		panic("stream: chunk size must be greater than zero")
+	}
+
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+
+		chunk := make([]T, 0, size)
+		for item := range in {
+			chunk = append(chunk, item)
+			if len(chunk) == size {
+				out <- chunk
+				chunk = make([]T, 0, size) // The receiver owns the emitted chunk.
+			}
+		}
+		if len(chunk) > 0 {
+			out <- chunk
+		}
+	}()
+	return out
+}
+
+// Flatten forwards the elements of each slice received from in one by one,
+// preserving their order. It is the inverse of Chunk. The returned channel is
+// closed once in is closed.
+func Flatten[T any](in <-chan []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		for chunk := range in {
+			for _, item := range chunk {
+				out <- item
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,57 @@
+package stream
+
+import "testing"
+
+// TestChunk tests that items are grouped into full chunks followed by a final partial one.
+func TestChunk(t *testing.T) {
+	chunks := collect(Chunk(feed(1, 2, 3, 4, 5, 6, 7), 3))
+
+	want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if len(chunks) != len(want) {
+		t.Fatalf("Expected %d chunks, got %v", len(want), chunks)
+	}
+	for i := range want {
+		if len(chunks[i]) != len(want[i]) {
+			t.Fatalf("Chunk %d = %v; want %v", i, chunks[i], want[i])
+		}
+		for j := range want[i] {
+			if chunks[i][j] != want[i][j] {
+				t.Fatalf("Chunk %d = %v; want %v", i, chunks[i], want[i])
+			}
+		}
+	}
+}
+
+// TestChunk_Exact tests that no empty chunk is emitted when the items divide evenly.
+func TestChunk_Exact(t *testing.T) {
+	chunks := collect(Chunk(feed(1, 2, 3, 4), 2))
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %v", chunks)
+	}
+	if chunks := collect(Chunk(feed[int](), 2)); len(chunks) != 0 {
+		t.Fatalf("Expected no chunks for an empty stream, got %v", chunks)
+	}
+}
+
+// TestFlatten tests that slices are flattened into their elements in order.
+func TestFlatten(t *testing.T) {
+	got := collect(Flatten(feed([]int{1, 2}, nil, []int{3})))
+
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestFlatten_Chunk tests that Flatten undoes Chunk.
+func TestFlatten_Chunk(t *testing.T) {
+	got := collect(Flatten(Chunk(feed(1, 2, 3, 4, 5), 2)))
+	if len(got) != 5 || got[0] != 1 || got[4] != 5 {
+		t.Fatalf("Expected 1 through 5, got %v", got)
+	}
+}
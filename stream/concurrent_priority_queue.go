@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// ConcurrentPriorityQueue is a PriorityQueue guarded by a mutex that performs the container/heap
+// operations itself, so callers deal only in values and priorities and cannot break the heap
+// invariant. The highest priority value is retrieved first. It is safe for concurrent use by multiple
+// goroutines.
+type ConcurrentPriorityQueue[T any] struct {
+	pq PriorityQueue[T] // Underlying heap.
+	mu sync.Mutex       // Mutex to protect concurrent access to pq.
+}
+
+// NewConcurrentPriorityQueue creates a new, empty ConcurrentPriorityQueue.
+func NewConcurrentPriorityQueue[T any]() *ConcurrentPriorityQueue[T] {
+	return &ConcurrentPriorityQueue[T]{}
+}
+
+// Len returns the number of values in the queue.
+func (q *ConcurrentPriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.pq.Len()
+}
+
+// Push adds value to the queue with the given priority.
+func (q *ConcurrentPriorityQueue[T]) Push(value T, priority int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	heap.Push(&q.pq, &Item[T]{value: value, priority: priority})
+}
+
+// Pop removes and returns the highest priority value. It returns false if the queue is empty.
+func (q *ConcurrentPriorityQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pq.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&q.pq).(*Item[T]).value, true
+}
+
+// Peek returns the highest priority value without removing it. It returns false if the queue is
+// empty.
+func (q *ConcurrentPriorityQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.pq.PeekValue()
+}
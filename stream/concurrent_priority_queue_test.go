@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestConcurrentPriorityQueue_PushPop tests that values are popped highest priority first.
+func TestConcurrentPriorityQueue_PushPop(t *testing.T) {
+	q := NewConcurrentPriorityQueue[string]()
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Expected Pop on an empty queue to fail")
+	}
+	if _, ok := q.Peek(); ok {
+		t.Fatal("Expected Peek on an empty queue to fail")
+	}
+
+	q.Push("low", 1)
+	q.Push("high", 3)
+	q.Push("mid", 2)
+
+	if v, ok := q.Peek(); !ok || v != "high" {
+		t.Fatalf("q.Peek() = %q, %v; want %q, %v", v, ok, "high", true)
+	}
+	for _, want := range []string{"high", "mid", "low"} {
+		if v, ok := q.Pop(); !ok || v != want {
+			t.Fatalf("q.Pop() = %q, %v; want %q, %v", v, ok, want, true)
+		}
+	}
+	if n := q.Len(); n != 0 {
+		t.Fatalf("Expected an empty queue, got length %d", n)
+	}
+}
+
+// TestConcurrentPriorityQueue_Concurrent tests concurrent pushes and pops for data races and a valid heap.
+func TestConcurrentPriorityQueue_Concurrent(t *testing.T) {
+	q := NewConcurrentPriorityQueue[int]()
+	var wg sync.WaitGroup
+	var popped int64
+	var mu sync.Mutex
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.Push(i, (g*100+i)%37)
+				q.Peek()
+				if i%2 == 0 {
+					if _, ok := q.Pop(); ok {
+						mu.Lock()
+						popped++
+						mu.Unlock()
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := int64(q.Len()); n+popped != 800 {
+		t.Fatalf("Expected %d values left, got %d", 800-popped, n)
+	}
+	if err := q.pq.validate(); err != nil {
+		t.Fatalf("Expected a valid heap, got %v", err)
+	}
+	last := 1 << 30
+	for q.Len() > 0 {
+		top := q.pq.Peek().priority
+		if top > last {
+			t.Fatalf("Popped priority %d after %d", top, last)
+		}
+		last = top
+		q.Pop()
+	}
+}
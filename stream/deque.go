@@ -0,0 +1,97 @@
+package stream
+
+import "sync"
+
+// minDequeSize is the initial capacity of a Deque's ring buffer.
+const minDequeSize = 16
+
+// Deque is a generic double-ended queue supporting push and pop at both ends. It is backed by a ring
+// buffer that doubles in size as needed and is safe for concurrent use by multiple goroutines.
+type Deque[T any] struct {
+	buf  []T        // Ring buffer; its length is always a power of two.
+	head int        // Index of the front item.
+	len  int        // Number of items in the deque.
+	mu   sync.Mutex // Mutex to protect concurrent access to the deque.
+}
+
+// NewDeque creates a new, empty Deque.
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{
+		buf: make([]T, minDequeSize),
+	}
+}
+
+// Len returns the number of items in the deque.
+func (d *Deque[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.len
+}
+
+// PushFront adds value at the front of the deque.
+func (d *Deque[T]) PushFront(value T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.grow()
+	d.head = (d.head - 1) & (len(d.buf) - 1)
+	d.buf[d.head] = value
+	d.len++
+}
+
+// PushBack adds value at the back of the deque.
+func (d *Deque[T]) PushBack(value T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.grow()
+	d.buf[(d.head+d.len)&(len(d.buf)-1)] = value
+	d.len++
+}
+
+// PopFront removes and returns the item at the front of the deque. It returns false if the deque is
+// empty.
+func (d *Deque[T]) PopFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+	value := d.buf[d.head]
+	d.buf[d.head] = zero // Release the reference for the garbage collector.
+	d.head = (d.head + 1) & (len(d.buf) - 1)
+	d.len--
+	return value, true
+}
+
+// PopBack removes and returns the item at the back of the deque. It returns false if the deque is
+// empty.
+func (d *Deque[T]) PopBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+	i := (d.head + d.len - 1) & (len(d.buf) - 1)
+	value := d.buf[i]
+	d.buf[i] = zero
+	d.len--
+	return value, true
+}
+
+// grow doubles the ring buffer if it is full, unwrapping the items so that the front is at index 0.
+func (d *Deque[T]) grow() {
+	if d.len < len(d.buf) {
+		return
+	}
+	buf := make([]T, len(d.buf)*2)
+	n := copy(buf, d.buf[d.head:])
+	copy(buf[n:], d.buf[:d.head])
+	d.buf = buf
+	d.head = 0
+}
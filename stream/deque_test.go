@@ -0,0 +1,129 @@
+package stream
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestDeque_Operations tests push and pop at both ends.
+func TestDeque_Operations(t *testing.T) {
+	d := NewDeque[int]()
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+
+	if n := d.Len(); n != 4 {
+		t.Fatalf("Expected length 4, got %d", n)
+	}
+	if v, ok := d.PopFront(); !ok || v != 0 {
+		t.Errorf("PopFront() = %d, %v; want 0, true", v, ok)
+	}
+	if v, ok := d.PopBack(); !ok || v != 3 {
+		t.Errorf("PopBack() = %d, %v; want 3, true", v, ok)
+	}
+	if v, ok := d.PopBack(); !ok || v != 2 {
+		t.Errorf("PopBack() = %d, %v; want 2, true", v, ok)
+	}
+	if v, ok := d.PopFront(); !ok || v != 1 {
+		t.Errorf("PopFront() = %d, %v; want 1, true", v, ok)
+	}
+	if _, ok := d.PopFront(); ok {
+		t.Error("Expected PopFront on empty deque to report false")
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Error("Expected PopBack on empty deque to report false")
+	}
+}
+
+// TestDeque_Growth tests that the deque grows past its initial size while preserving order across wrap-around.
+func TestDeque_Growth(t *testing.T) {
+	d := NewDeque[int]()
+	const n = 1000
+
+	// Mix front and back pushes so that the ring buffer wraps before growing.
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			d.PushBack(i)
+		} else {
+			d.PushFront(-i)
+		}
+	}
+	if l := d.Len(); l != n {
+		t.Fatalf("Expected length %d, got %d", n, l)
+	}
+
+	for i := n - 1; i >= 1; i -= 2 {
+		if v, _ := d.PopFront(); v != -i {
+			t.Fatalf("PopFront() = %d; want %d", v, -i)
+		}
+	}
+	for i := n - 2; i >= 0; i -= 2 {
+		if v, _ := d.PopBack(); v != i {
+			t.Fatalf("PopBack() = %d; want %d", v, i)
+		}
+	}
+}
+
+// TestDeque_Concurrency tests that concurrent pushes and pops neither lose nor duplicate items.
+func TestDeque_Concurrency(t *testing.T) {
+	d := NewDeque[int]()
+	const producers, perProducer = 8, 500
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if i%2 == 0 {
+					d.PushBack(p*perProducer + i)
+				} else {
+					d.PushFront(p*perProducer + i)
+				}
+			}
+		}(p)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				var v int
+				var ok bool
+				if c%2 == 0 {
+					v, ok = d.PopFront()
+				} else {
+					v, ok = d.PopBack()
+				}
+				if ok {
+					mu.Lock()
+					if seen[v] {
+						t.Errorf("Item %d popped twice", v)
+					}
+					seen[v] = true
+					mu.Unlock()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	for {
+		v, ok := d.PopFront()
+		if !ok {
+			break
+		}
+		if seen[v] {
+			t.Errorf("Item %d popped twice", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) != producers*perProducer {
+		t.Fatalf("Expected %d distinct items, got %d", producers*perProducer, len(seen))
+	}
+}
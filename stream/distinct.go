@@ -0,0 +1,80 @@
+package stream
+
+// Distinct forwards each item from in the first time it is seen and drops any
+// later repeat. The returned channel is closed once in is closed. Distinct
+// remembers every item it has forwarded, so memory grows with the number of
+// distinct items.
+func Distinct[T comparable](in <-chan T) <-chan T {
+	return DistinctFunc(in, func(item T) T { return item })
+}
+
+// DistinctFunc is like Distinct but compares items by the key derived with
+// keyFn, so T itself does not need to be comparable.
+func DistinctFunc[T any, K comparable](in <-chan T, keyFn func(T) K) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		seen := make(map[K]struct{})
+		for item := range in {
+			key := keyFn(item)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out <- item
+		}
+	}()
+	return out
+}
+
+// Dedupe forwards items from in, dropping any item equal to the one forwarded
+// immediately before it. Unlike Distinct, an item may reappear later once a
+// different item has been seen in between. The returned channel is closed once
+// in is closed.
+func Dedupe[T comparable](in <-chan T) <-chan T {
+	return DedupeFunc(in, func(item T) T { return item })
+}
+
+// DedupeFunc is like Dedupe but compares consecutive items by the key derived
+// with keyFn, so T itself does not need to be comparable.
+func DedupeFunc[T any, K comparable](in <-chan T, keyFn func(T) K) <-chan T {
+	return dropRepeats(in, keyFn, func(a, b K) bool { return a == b })
+}
+
+// OnChange forwards an item only when it differs from the previously forwarded
+// one, so a run of identical values is emitted once, at the transition. It is
+// the same as Dedupe, under the name used for state-change streams.
+func OnChange[T comparable](in <-chan T) <-chan T {
+	return Dedupe(in)
+}
+
+// OnChangeFunc is like OnChange but compares consecutive items with equal, for
+// items that are not comparable or whose equality is looser than ==.
+func OnChangeFunc[T any](in <-chan T, equal func(a, b T) bool) <-chan T {
+	return dropRepeats(in, func(item T) T { return item }, equal)
+}
+
+// dropRepeats forwards items from in, dropping any item whose key, derived
+// with keyFn, equal reports the same as that of the item forwarded before it.
+// keyFn is called once per item. The returned channel is closed once in is
+// closed.
+func dropRepeats[T, K any](in <-chan T, keyFn func(T) K, equal func(a, b K) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		var last K
+		first := true
+		for item := range in {
+			key := keyFn(item)
+			if !first && equal(last, key) {
+				continue
+			}
+			first = false
+			last = key
+			out <- item
+		}
+	}()
+	return out
+}
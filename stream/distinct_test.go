@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+// event is a non-comparable test item whose identity is its id field only.
+type event struct {
+	id   int
+	tags []string
+}
+
+// feed returns a closed channel pre-filled with items.
+func feed[T any](items ...T) <-chan T {
+	ch := make(chan T, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+	return ch
+}
+
+// collect drains ch into a slice.
+func collect[T any](ch <-chan T) []T {
+	var items []T
+	for item := range ch {
+		items = append(items, item)
+	}
+	return items
+}
+
+// TestDistinct tests that repeated items are dropped regardless of position.
+func TestDistinct(t *testing.T) {
+	got := collect(Distinct(feed(1, 2, 1, 3, 2, 4)))
+	want := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestDistinctFunc tests that items equal only on the derived key are deduplicated.
+func TestDistinctFunc(t *testing.T) {
+	in := feed(
+		event{id: 1, tags: []string{"a"}},
+		event{id: 2},
+		event{id: 1, tags: []string{"b"}}, // Same key as the first event.
+		event{id: 3},
+	)
+
+	got := collect(DistinctFunc(in, func(e event) int { return e.id }))
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(got))
+	}
+	for i, id := range []int{1, 2, 3} {
+		if got[i].id != id {
+			t.Errorf("Expected id %d at position %d, got %d", id, i, got[i].id)
+		}
+	}
+	if got[0].tags[0] != "a" {
+		t.Errorf("Expected the first occurrence to be kept, got tags %v", got[0].tags)
+	}
+}
+
+// TestDedupe tests that only consecutive duplicates are dropped.
+func TestDedupe(t *testing.T) {
+	got := collect(Dedupe(feed(1, 1, 2, 2, 2, 1, 3, 3)))
+	want := []int{1, 2, 1, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestDedupeFunc tests that consecutive items equal on the derived key are collapsed while differing ones pass.
+func TestDedupeFunc(t *testing.T) {
+	in := feed(
+		event{id: 1},
+		event{id: 1, tags: []string{"x"}},
+		event{id: 2},
+		event{id: 1},
+	)
+
+	got := collect(DedupeFunc(in, func(e event) int { return e.id }))
+	var ids []int
+	for _, e := range got {
+		ids = append(ids, e.id)
+	}
+	want := []int{1, 2, 1}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected ids %v, got %v", want, ids)
+	}
+}
+
+// TestOnChange tests that a run of identical values is emitted once and alternating values all pass.
+func TestOnChange(t *testing.T) {
+	got := collect(OnChange(feed(7, 7, 7, 7)))
+	if want := []int{7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = collect(OnChange(feed(1, 2, 1, 2, 1)))
+	if want := []int{1, 2, 1, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestOnChangeFunc tests that non-comparable items are compared with the equality function.
+func TestOnChangeFunc(t *testing.T) {
+	readings := feed([]float64{1, 2}, []float64{1, 2}, []float64{1, 3}, []float64{1, 3}, []float64{1, 2})
+	equal := func(a, b []float64) bool { return reflect.DeepEqual(a, b) }
+
+	got := collect(OnChangeFunc(readings, equal))
+	want := [][]float64{{1, 2}, {1, 3}, {1, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
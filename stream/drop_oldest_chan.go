@@ -0,0 +1,68 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DropOldestChan is a generic bounded buffer that never blocks the sender. When the buffer is full,
+// the oldest buffered item is discarded to make room for the new one. It generalizes LatestItemQueue
+// to arbitrary buffer sizes and is suited for sinks, such as metrics, that can tolerate loss but must
+// never stall the hot path.
+type DropOldestChan[T any] struct {
+	dropped uint64 // Number of items discarded so far; accessed atomically and kept first for 64-bit alignment.
+
+	channel chan T     // Buffered channel holding up to size items.
+	mu      sync.Mutex // Serializes senders so that drop-and-send is atomic with respect to other senders.
+}
+
+// NewDropOldestChan creates a new DropOldestChan with room for size items.
+func NewDropOldestChan[T any](size int) *DropOldestChan[T] {
+	if size <= 0 {
+		panic("stream: size must be greater than zero")
+	}
+	return &DropOldestChan[T]{
+		channel: make(chan T, size),
+	}
+}
+
+// Send enqueues an item without blocking. If the buffer is full, the oldest item is dropped and
+// counted in Dropped.
+func (c *DropOldestChan[T]) Send(item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.push(item); ok {
+		atomic.AddUint64(&c.dropped, 1)
+	}
+}
+
+// push enqueues item, discarding the oldest buffered item first if the buffer is full, and returns
+// the discarded item, if any. The caller must serialize senders, for example by holding c.mu.
+func (c *DropOldestChan[T]) push(item T) (dropped T, ok bool) {
+	for {
+		select {
+		case c.channel <- item:
+			return dropped, ok
+		default:
+		}
+
+		// The buffer is full, discard the oldest item. The consumer may have drained it in the
+		// meantime, in which case nothing is dropped and the send is retried.
+		select {
+		case dropped = <-c.channel:
+			ok = true
+		default:
+		}
+	}
+}
+
+// Out provides access to the underlying channel for consuming items in the order they were sent.
+func (c *DropOldestChan[T]) Out() <-chan T {
+	return c.channel
+}
+
+// Dropped returns the number of items discarded because the buffer was full.
+func (c *DropOldestChan[T]) Dropped() uint64 {
+	return atomic.LoadUint64(&c.dropped)
+}
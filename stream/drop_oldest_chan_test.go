@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestDropOldestChan_DropsOldest tests that a full buffer discards its oldest items first.
+func TestDropOldestChan_DropsOldest(t *testing.T) {
+	c := NewDropOldestChan[int](3)
+
+	for i := 1; i <= 5; i++ {
+		c.Send(i) // 1 and 2 should be dropped.
+	}
+
+	if d := c.Dropped(); d != 2 {
+		t.Errorf("Expected 2 dropped items, got %d", d)
+	}
+	for _, expected := range []int{3, 4, 5} {
+		if item := <-c.Out(); item != expected {
+			t.Errorf("Expected %d, got %d", expected, item)
+		}
+	}
+}
+
+// TestDropOldestChan_NoDropsBelowCapacity tests that nothing is dropped while the buffer has room.
+func TestDropOldestChan_NoDropsBelowCapacity(t *testing.T) {
+	c := NewDropOldestChan[int](4)
+	c.Send(1)
+	c.Send(2)
+	<-c.Out()
+	c.Send(3)
+	c.Send(4)
+	c.Send(5)
+
+	if d := c.Dropped(); d != 0 {
+		t.Errorf("Expected 0 dropped items, got %d", d)
+	}
+	if n := len(c.Out()); n != 4 {
+		t.Errorf("Expected 4 buffered items, got %d", n)
+	}
+}
+
+// TestDropOldestChan_ConcurrentSend tests that the drop counter is accurate under concurrent senders.
+func TestDropOldestChan_ConcurrentSend(t *testing.T) {
+	const size, senders, perSender = 8, 10, 100
+	c := NewDropOldestChan[int](size)
+
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perSender; j++ {
+				c.Send(j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if buffered := len(c.Out()); uint64(buffered)+c.Dropped() != senders*perSender {
+		t.Errorf("Expected buffered (%d) + dropped (%d) = %d", buffered, c.Dropped(), senders*perSender)
+	}
+}
@@ -0,0 +1,114 @@
+package stream
+
+import "time"
+
+// KV is a keyed item flowing through a stream. It is the input type of keyed
+// operators such as Join.
+type KV[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Joined is the record emitted by Join when items with the same key arrive on
+// both the left and right streams within the join window.
+type Joined[K comparable, A, B any] struct {
+	Key   K
+	Left  A
+	Right B
+}
+
+// pending is an unmatched item buffered by Join together with the time after
+// which it can no longer be matched.
+type pending[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Join correlates two keyed streams. Whenever an item arrives on one side and an
+// unexpired item with the same key is buffered from the other side, the two are
+// paired and emitted as a Joined record. Unmatched items are buffered for the
+// duration of window and dropped once it elapses. Items are matched one-to-one
+// in arrival order. The returned channel is closed once both inputs are closed.
+func Join[K comparable, A, B any](left <-chan KV[K, A], right <-chan KV[K, B], window time.Duration) <-chan Joined[K, A, B] {
+	if window <= 0 {
+		panic("stream: join window must be greater than zero")
+	}
+
+	out := make(chan Joined[K, A, B])
+	go func() {
+		defer close(out)
+
+		lbuf := make(map[K][]pending[A])
+		rbuf := make(map[K][]pending[B])
+
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+
+		for left != nil || right != nil {
+			select {
+			case kv, ok := <-left:
+				if !ok {
+					left = nil // Stop selecting on the closed input.
+					continue
+				}
+				now := time.Now()
+				if r, found := take(rbuf, kv.Key, now); found {
+					out <- Joined[K, A, B]{Key: kv.Key, Left: kv.Value, Right: r}
+					continue
+				}
+				lbuf[kv.Key] = append(lbuf[kv.Key], pending[A]{value: kv.Value, expires: now.Add(window)})
+			case kv, ok := <-right:
+				if !ok {
+					right = nil
+					continue
+				}
+				now := time.Now()
+				if l, found := take(lbuf, kv.Key, now); found {
+					out <- Joined[K, A, B]{Key: kv.Key, Left: l, Right: kv.Value}
+					continue
+				}
+				rbuf[kv.Key] = append(rbuf[kv.Key], pending[B]{value: kv.Value, expires: now.Add(window)})
+			case now := <-ticker.C:
+				expire(lbuf, now)
+				expire(rbuf, now)
+			}
+		}
+	}()
+	return out
+}
+
+// take removes and returns the oldest unexpired item buffered under key.
+// Expired items encountered along the way are discarded.
+func take[K comparable, V any](buf map[K][]pending[V], key K, now time.Time) (V, bool) {
+	items := buf[key]
+	for len(items) > 0 && !now.Before(items[0].expires) {
+		items = items[1:]
+	}
+	if len(items) == 0 {
+		delete(buf, key)
+		var zero V
+		return zero, false
+	}
+	v := items[0].value
+	if len(items) == 1 {
+		delete(buf, key)
+	} else {
+		buf[key] = items[1:]
+	}
+	return v, true
+}
+
+// expire drops every buffered item whose window has elapsed.
+func expire[K comparable, V any](buf map[K][]pending[V], now time.Time) {
+	for key, items := range buf {
+		i := 0
+		for i < len(items) && !now.Before(items[i].expires) {
+			i++
+		}
+		if i == len(items) {
+			delete(buf, key)
+		} else if i > 0 {
+			buf[key] = items[i:]
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+// TestJoin_MatchWithinWindow tests that items with the same key are joined when they arrive within the window.
+func TestJoin_MatchWithinWindow(t *testing.T) {
+	left := make(chan KV[string, int])
+	right := make(chan KV[string, string])
+	out := Join(left, right, time.Second)
+
+	left <- KV[string, int]{Key: "a", Value: 1}
+	right <- KV[string, string]{Key: "a", Value: "one"}
+
+	select {
+	case j := <-out:
+		if j.Key != "a" || j.Left != 1 || j.Right != "one" {
+			t.Errorf("Expected {a 1 one}, got %+v", j)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for joined item")
+	}
+
+	close(left)
+	close(right)
+	if _, ok := <-out; ok {
+		t.Fatal("Expected output channel to be closed")
+	}
+}
+
+// TestJoin_NoMatchOnDifferentKeys tests that items with different keys are not joined.
+func TestJoin_NoMatchOnDifferentKeys(t *testing.T) {
+	left := make(chan KV[string, int])
+	right := make(chan KV[string, int])
+	out := Join(left, right, time.Second)
+
+	left <- KV[string, int]{Key: "a", Value: 1}
+	right <- KV[string, int]{Key: "b", Value: 2}
+	close(left)
+	close(right)
+
+	if j, ok := <-out; ok {
+		t.Fatalf("Expected no joined item, got %+v", j)
+	}
+}
+
+// TestJoin_Expiry tests that unmatched items are dropped once the window elapses.
+func TestJoin_Expiry(t *testing.T) {
+	left := make(chan KV[int, int])
+	right := make(chan KV[int, int])
+	out := Join(left, right, 20*time.Millisecond)
+
+	left <- KV[int, int]{Key: 1, Value: 1}
+	time.Sleep(50 * time.Millisecond)
+	right <- KV[int, int]{Key: 1, Value: 2} // The left item has expired; this one is buffered instead.
+	left <- KV[int, int]{Key: 1, Value: 3}  // Matches the buffered right item.
+
+	select {
+	case j := <-out:
+		if j.Left != 3 || j.Right != 2 {
+			t.Errorf("Expected {1 3 2}, got %+v", j)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for joined item")
+	}
+
+	close(left)
+	close(right)
+	if j, ok := <-out; ok {
+		t.Fatalf("Expected no further joined items, got %+v", j)
+	}
+}
+
+// TestJoin_OneToOne tests that each buffered item is matched at most once, in arrival order.
+func TestJoin_OneToOne(t *testing.T) {
+	left := make(chan KV[int, int])
+	right := make(chan KV[int, int])
+	out := Join(left, right, time.Second)
+
+	left <- KV[int, int]{Key: 1, Value: 10}
+	left <- KV[int, int]{Key: 1, Value: 11}
+
+	go func() {
+		right <- KV[int, int]{Key: 1, Value: 20}
+		right <- KV[int, int]{Key: 1, Value: 21}
+		right <- KV[int, int]{Key: 1, Value: 22} // Nothing left to match.
+		close(left)
+		close(right)
+	}()
+
+	var got []Joined[int, int, int]
+	for j := range out {
+		got = append(got, j)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 joined items, got %d", len(got))
+	}
+	if got[0].Left != 10 || got[0].Right != 20 || got[1].Left != 11 || got[1].Right != 21 {
+		t.Errorf("Unexpected join order: %+v", got)
+	}
+}
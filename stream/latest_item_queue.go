@@ -1,50 +1,194 @@
 package stream
 
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueClosed is returned by LatestItemQueue.Consume once the queue has been closed and drained.
+var ErrQueueClosed = errors.New("stream: queue closed")
+
 // LatestItemQueue is a generic type-safe queue that ensures the consumer always receives the most recent item.
 // It is particularly useful in scenarios where processing speed varies and only the latest data is relevant,
 // such as real-time data processing or event handling systems.
 type LatestItemQueue[T any] struct {
-	channel chan T        // A channel that holds the latest item.
-	closed  chan struct{} // Indicator for closing the consume channel
+	buf     *DropOldestChan[T]  // Holds the latest item, or the latest n items, dropping the oldest when full.
+	closed  chan struct{}       // Indicator for closing the consume channel
+	less    func(a, b T) bool   // Ranking used in ranked mode; nil for overwrite-latest mode.
+	mu      sync.Mutex          // Serializes producers and Close, so no item is sent after the channel is closed.
+	onPanic func(recovered any) // Optional reporter for panics recovered by Run.
+	onDrop  func(dropped T)     // Optional callback receiving items discarded by Produce.
 }
 
 // NewLatestItemQueue creates a new instance of LatestItemQueue with a predefined buffer.
 // The buffer size is set to 1 to hold only the most recent item.
 func NewLatestItemQueue[T any]() *LatestItemQueue[T] {
+	return NewLatestNQueue[T](1)
+}
+
+// NewLatestNQueue creates a LatestItemQueue that buffers the n most recent items instead of just one.
+// When a new item is produced while n items are buffered, the oldest is discarded to make room, so
+// Produce never blocks. Consumers receive the buffered items in the order they were produced. The
+// items are buffered in a DropOldestChan, to which the queue adds closing and consuming.
+func NewLatestNQueue[T any](n int) *LatestItemQueue[T] {
+	if n <= 0 {
+		panic("stream: n must be greater than zero")
+	}
 	return &LatestItemQueue[T]{
-		channel: make(chan T, 1),
-		closed:  make(chan struct{}),
+		buf:    NewDropOldestChan[T](n),
+		closed: make(chan struct{}),
 	}
 }
 
+// NewRankedItemQueue creates a LatestItemQueue in ranked mode. Instead of keeping the most recent
+// item, the queue keeps the highest-ranked item produced since the last consume: a new item replaces
+// the buffered one only if less(buffered, new) reports true. Once the item is consumed, tracking
+// starts over with the next produced item. This is useful for surfacing, for example, the most severe
+// alert raised between polls.
+func NewRankedItemQueue[T any](less func(a, b T) bool) *LatestItemQueue[T] {
+	q := NewLatestItemQueue[T]()
+	q.less = less
+	return q
+}
+
 // Produce attempts to send an item to the queue.
 // If the queue is full (already holding an item), it discards the oldest item and enqueues the new one,
-// ensuring that the queue always contains the most recent item.
+// ensuring that the queue always contains the most recent item. In ranked mode, the buffered item is
+// only replaced if the new item ranks higher. Produce on a closed queue does nothing.
 func (q *LatestItemQueue[T]) Produce(item T) {
+	if dropped, ok := q.produce(item); ok && q.onDrop != nil {
+		q.onDrop(dropped)
+	}
+}
+
+// produce implements Produce and returns the item it discarded, if any.
+func (q *LatestItemQueue[T]) produce(item T) (dropped T, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	select {
-	case <-q.closed: // Check if the queue is closed to prevent sending on closed channel
-		return
-	case q.channel <- item: // Try sending the item to the channel.
+	case <-q.closed: // Checked under q.mu, so Close cannot close the channel before the send below.
+		return dropped, false
+	default:
+	}
+
+	if q.less != nil {
+		return q.produceRanked(item)
+	}
+	return q.buf.push(item) // Producers are serialized by q.mu.
+}
+
+// produceRanked implements Produce in ranked mode and returns the lower-ranked item it discarded, if
+// any. The caller must hold q.mu.
+func (q *LatestItemQueue[T]) produceRanked(item T) (dropped T, ok bool) {
+	select {
+	case q.buf.channel <- item:
+		return dropped, false
+	default:
+	}
+
+	// The channel is full. Take the buffered item back and keep whichever ranks higher. If the
+	// consumer drained the channel in the meantime, the new item is simply sent.
+	select {
+	case buffered := <-q.buf.channel:
+		dropped, ok = buffered, true
+		if !q.less(buffered, item) {
+			item, dropped = buffered, item
+		}
 	default:
-		// The channel is full, discard the oldest item and send the new one.
-		<-q.channel       // Discard the oldest item.
-		q.channel <- item // Send the new item.
 	}
+	q.buf.channel <- item // Producers are serialized, so the channel has room.
+	return dropped, ok
 }
 
 // ConsumeChannel provides access to the underlying channel for consuming items.
 // Consumers can read from this channel to receive the most recent item available.
 func (q *LatestItemQueue[T]) ConsumeChannel() <-chan T {
-	return q.channel
+	return q.buf.channel
+}
+
+// TryConsume returns the buffered item without blocking. It returns false if no item is buffered or
+// the queue is closed.
+func (q *LatestItemQueue[T]) TryConsume() (T, bool) {
+	select {
+	case item, ok := <-q.buf.channel:
+		return item, ok
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+// Consume blocks until an item is available and returns it. It returns ctx.Err() if ctx is done first,
+// or ErrQueueClosed if the queue is closed and no item is left. It starts no goroutines, so abandoning
+// a Consume through ctx leaks nothing.
+func (q *LatestItemQueue[T]) Consume(ctx context.Context) (T, error) {
+	select {
+	case item, ok := <-q.buf.channel:
+		if !ok {
+			return item, ErrQueueClosed
+		}
+		return item, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
 }
 
-// Close safely closes the consume channel, ensuring no more items can be sent.
+// SetOnDrop sets fn to be called with every item that Produce discards before it was consumed: the
+// overwritten item, or in ranked mode whichever of the buffered and new items ranks lower. fn is
+// called on the producing goroutine after the queue's lock has been released, so it does not hold up
+// other producers or consumers, but it delays the return of that Produce and should be quick. It must
+// be called before the queue is used.
+func (q *LatestItemQueue[T]) SetOnDrop(fn func(dropped T)) {
+	q.onDrop = fn
+}
+
+// OnPanic sets fn to be called with the recovered value whenever a handler passed to Run panics.
+// It must be called before Run is started.
+func (q *LatestItemQueue[T]) OnPanic(fn func(recovered any)) {
+	q.onPanic = fn
+}
+
+// Run consumes items from the queue, calling handler for each one, until the queue is closed or ctx
+// is cancelled. A panic in handler is recovered, reported to the OnPanic callback if one is set, and
+// consumption continues with the next item. Run blocks and is typically started in its own goroutine.
+func (q *LatestItemQueue[T]) Run(ctx context.Context, handler func(T)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-q.buf.channel:
+			if !ok {
+				return
+			}
+			q.handle(handler, item)
+		}
+	}
+}
+
+// handle calls handler with item, recovering from a panic.
+func (q *LatestItemQueue[T]) handle(handler func(T), item T) {
+	defer func() {
+		if r := recover(); r != nil && q.onPanic != nil {
+			q.onPanic(r)
+		}
+	}()
+	handler(item)
+}
+
+// Close safely closes the consume channel, ensuring no more items can be sent. It is safe to call
+// concurrently with Produce and more than once.
 func (q *LatestItemQueue[T]) Close() {
+	q.mu.Lock() // Wait for an in-flight Produce to finish.
+	defer q.mu.Unlock()
+
 	select {
 	case <-q.closed: // Prevent closing more than once
 		return
 	default:
-		close(q.closed)  // Close the closed channel to signal closure
-		close(q.channel) // Close the channel to signal no more sends
+		close(q.closed)      // Close the closed channel to signal closure
+		close(q.buf.channel) // Close the channel to signal no more sends
 	}
 }
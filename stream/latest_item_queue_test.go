@@ -1,6 +1,9 @@
 package stream
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 )
@@ -119,3 +122,347 @@ func TestLatestItemQueue_ConcurrentProduce(t *testing.T) {
 		t.Fatal("Timed out waiting for item")
 	}
 }
+
+func TestRankedItemQueue_KeepsHighest(t *testing.T) {
+	queue := NewRankedItemQueue(func(a, b int) bool { return a < b })
+	defer queue.Close()
+
+	for _, severity := range []int{2, 5, 1, 4} {
+		queue.Produce(severity)
+	}
+
+	select {
+	case item := <-queue.ConsumeChannel():
+		if item != 5 {
+			t.Errorf("Expected 5, got %d", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for item")
+	}
+
+	// Tracking resets after a consume, so a lower-ranked item is delivered next.
+	queue.Produce(1)
+	queue.Produce(3)
+	select {
+	case item := <-queue.ConsumeChannel():
+		if item != 3 {
+			t.Errorf("Expected 3 after reset, got %d", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for item")
+	}
+}
+
+func TestRankedItemQueue_ConcurrentProduce(t *testing.T) {
+	queue := NewRankedItemQueue(func(a, b int) bool { return a < b })
+	defer queue.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(val int) {
+			defer wg.Done()
+			queue.Produce(val)
+		}(i)
+	}
+	wg.Wait()
+
+	if item := <-queue.ConsumeChannel(); item != 99 {
+		t.Errorf("Expected 99, got %d", item)
+	}
+}
+
+func TestLatestItemQueue_Run(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+	received := make(chan int)
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		queue.Run(context.Background(), func(item int) { received <- item })
+	}()
+
+	for _, expected := range []int{1, 2} {
+		queue.Produce(expected)
+		select {
+		case item := <-received:
+			if item != expected {
+				t.Errorf("Expected %d, got %d", expected, item)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for handler")
+		}
+	}
+
+	queue.Close()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after Close")
+	}
+}
+
+func TestLatestItemQueue_RunCancel(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+	defer queue.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		queue.Run(ctx, func(int) {})
+	}()
+
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after cancel")
+	}
+}
+
+func TestLatestItemQueue_RunRecoversPanic(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+	reported := make(chan any, 1)
+	queue.OnPanic(func(r any) { reported <- r })
+
+	received := make(chan int)
+	go queue.Run(context.Background(), func(item int) {
+		if item == 1 {
+			panic("bad item")
+		}
+		received <- item
+	})
+	defer queue.Close()
+
+	queue.Produce(1)
+	select {
+	case r := <-reported:
+		if r != "bad item" {
+			t.Errorf("Expected \"bad item\", got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for panic report")
+	}
+
+	queue.Produce(2)
+	select {
+	case item := <-received:
+		if item != 2 {
+			t.Errorf("Expected 2, got %d", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to keep consuming after a panic")
+	}
+}
+
+func TestRankedItemQueue_ProduceAfterClose(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		queue := NewRankedItemQueue(func(a, b int) bool { return a < b })
+		queue.Close()
+		queue.Produce(1) // Must not panic.
+	}
+}
+
+func TestLatestItemQueue_TryConsume(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+
+	if _, ok := queue.TryConsume(); ok {
+		t.Fatal("Expected TryConsume on an empty queue to fail")
+	}
+
+	queue.Produce(1)
+	queue.Produce(2)
+	if item, ok := queue.TryConsume(); !ok || item != 2 {
+		t.Fatalf("queue.TryConsume() = %d, %v; want %d, %v", item, ok, 2, true)
+	}
+	if _, ok := queue.TryConsume(); ok {
+		t.Fatal("Expected the item to be consumed only once")
+	}
+
+	queue.Close()
+	if _, ok := queue.TryConsume(); ok {
+		t.Fatal("Expected TryConsume on a closed queue to fail")
+	}
+}
+
+func TestLatestItemQueue_TryConsumeWhileProducing(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := 1; i <= 10000; i++ {
+			queue.Produce(i)
+		}
+	}()
+
+	last := 0
+	for {
+		if item, ok := queue.TryConsume(); ok {
+			if item <= last {
+				t.Fatalf("Consumed %d after %d", item, last)
+			}
+			last = item
+		}
+		select {
+		case <-done: // Produce never blocked on a drained channel.
+			if item, ok := queue.TryConsume(); ok {
+				last = item
+			}
+			if last != 10000 {
+				t.Fatalf("Expected to end with the last item, got %d", last)
+			}
+			return
+		default:
+		}
+	}
+}
+
+func TestLatestItemQueue_Consume(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Produce(7)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if item, err := queue.Consume(ctx); err != nil || item != 7 {
+		t.Fatalf("queue.Consume() = %d, %v; want %d, nil", item, err, 7)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := queue.Consume(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	queue.Produce(8)
+	queue.Close()
+	if item, err := queue.Consume(ctx); err != nil || item != 8 {
+		t.Fatalf("Expected the buffered item before closure, got %d, %v", item, err)
+	}
+	if _, err := queue.Consume(ctx); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("Expected %v, got %v", ErrQueueClosed, err)
+	}
+}
+
+func TestLatestItemQueue_ProduceCloseRace(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		queue := NewLatestItemQueue[int]()
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				<-start
+				for i := 0; i < 100; i++ {
+					queue.Produce(g*100 + i) // Must not panic once Close has run.
+				}
+			}(g)
+		}
+
+		close(start)
+		queue.Close()
+		wg.Wait()
+
+		queue.Produce(-1) // Produce after Close is a no-op.
+		for range queue.ConsumeChannel() {
+		}
+	}
+}
+
+func TestLatestNQueue_DropsOldest(t *testing.T) {
+	queue := NewLatestNQueue[int](3)
+	for i := 1; i <= 5; i++ {
+		queue.Produce(i) // Never blocks, even without a consumer.
+	}
+	queue.Close()
+
+	var got []int
+	for item := range queue.ConsumeChannel() {
+		got = append(got, item)
+	}
+	want := []int{3, 4, 5}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestLatestNQueue_ConcurrentProduce(t *testing.T) {
+	queue := NewLatestNQueue[int](4)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				queue.Produce(g*1000 + i)
+			}
+		}(g)
+	}
+
+	// A slow consumer only sees some items, but each producer's items stay in order.
+	done := make(chan struct{})
+	last := make(map[int]int)
+	go func() {
+		defer close(done)
+		for item := range queue.ConsumeChannel() {
+			g := item / 1000
+			if prev, ok := last[g]; ok && item <= prev {
+				t.Errorf("Consumed %d after %d", item, prev)
+			}
+			last[g] = item
+			time.Sleep(time.Microsecond)
+		}
+	}()
+
+	wg.Wait()
+	queue.Close()
+	<-done
+}
+
+func TestLatestItemQueue_SetOnDrop(t *testing.T) {
+	queue := NewLatestItemQueue[int]()
+	var dropped []int
+	queue.SetOnDrop(func(item int) { dropped = append(dropped, item) })
+
+	queue.Produce(1)
+	if item := <-queue.ConsumeChannel(); item != 1 {
+		t.Fatalf("Expected 1, got %d", item)
+	}
+	queue.Produce(2) // The channel was drained, so nothing is dropped.
+	if len(dropped) != 0 {
+		t.Fatalf("Expected no drops on the normal path, got %v", dropped)
+	}
+
+	queue.Produce(3) // Overwrites the unconsumed 2.
+	if len(dropped) != 1 || dropped[0] != 2 {
+		t.Fatalf("Expected 2 to be dropped, got %v", dropped)
+	}
+}
+
+func TestRankedItemQueue_SetOnDrop(t *testing.T) {
+	queue := NewRankedItemQueue(func(a, b int) bool { return a < b })
+	var dropped []int
+	queue.SetOnDrop(func(item int) { dropped = append(dropped, item) })
+
+	queue.Produce(5)
+	queue.Produce(3) // Ranks lower than the buffered 5, so the new item is dropped.
+	queue.Produce(9) // Ranks higher, so the buffered 5 is dropped.
+
+	if len(dropped) != 2 || dropped[0] != 3 || dropped[1] != 5 {
+		t.Fatalf("Expected [3 5] to be dropped, got %v", dropped)
+	}
+	if item := <-queue.ConsumeChannel(); item != 9 {
+		t.Fatalf("Expected 9, got %d", item)
+	}
+}
@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+)
+
+// errInitPanicked is returned to callers that waited on an init function that panicked.
+var errInitPanicked = errors.New("stream: lazy init function panicked")
+
+// lazyCall is a run of a LazyValue's init function. Callers that arrive while it runs wait on done
+// and then read value and err.
+type lazyCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// LazyValue computes a value on first use and then returns it on every later call. Unlike sync.Once,
+// init can fail: by default a failed init is retried by the next Get, while a successful one is never
+// run again. Concurrent callers share a single run of init. It is safe for concurrent use by multiple
+// goroutines.
+type LazyValue[T any] struct {
+	init     func() (T, error) // Computes the value.
+	sticky   bool              // Whether a failed init is final instead of retried.
+	resolved bool              // Whether value and err are final.
+	value    T                 // The computed value, once resolved.
+	err      error             // The final error, once resolved with sticky errors.
+	call     *lazyCall[T]      // The run of init in progress, if any.
+	mu       sync.Mutex        // Mutex to protect the fields above.
+}
+
+// NewLazyValue creates a new LazyValue computed by init.
+func NewLazyValue[T any](init func() (T, error)) *LazyValue[T] {
+	if init == nil {
+		panic("stream: init function must not be nil")
+	}
+	return &LazyValue[T]{init: init}
+}
+
+// RetryOnError sets whether a Get after a failed init runs init again, which is the default. With
+// retry disabled, the first error is returned by every later Get. It must be called before the first
+// Get.
+func (l *LazyValue[T]) RetryOnError(retry bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sticky = !retry
+}
+
+// Get returns the value, running init if it has not succeeded yet. Callers that arrive while init
+// runs wait for it and receive the same result.
+func (l *LazyValue[T]) Get() (T, error) {
+	l.mu.Lock()
+	if l.resolved {
+		l.mu.Unlock()
+		return l.value, l.err
+	}
+	if c := l.call; c != nil {
+		l.mu.Unlock()
+		<-c.done
+		return c.value, c.err
+	}
+	c := &lazyCall[T]{done: make(chan struct{})}
+	l.call = c
+	l.mu.Unlock()
+
+	l.run(c)
+	return c.value, c.err
+}
+
+// run calls init for c, records the outcome and releases the callers waiting on c.
+func (l *LazyValue[T]) run(c *lazyCall[T]) {
+	c.err = errInitPanicked // Overwritten unless init panics.
+	defer func() {
+		l.mu.Lock()
+		if c.err == nil || l.sticky {
+			l.resolved = true
+			l.value, l.err = c.value, c.err
+		}
+		l.call = nil
+		l.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.value, c.err = l.init()
+}
@@ -0,0 +1,97 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestLazyValue_Once tests that init runs once on success.
+func TestLazyValue_Once(t *testing.T) {
+	var runs int64
+	l := NewLazyValue(func() (int, error) {
+		atomic.AddInt64(&runs, 1)
+		return 42, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if v, err := l.Get(); err != nil || v != 42 {
+			t.Fatalf("l.Get() = %d, %v; want %d, nil", v, err, 42)
+		}
+	}
+	if n := atomic.LoadInt64(&runs); n != 1 {
+		t.Fatalf("Expected init to run once, ran %d times", n)
+	}
+}
+
+// TestLazyValue_Concurrent tests that concurrent first callers share one init run.
+func TestLazyValue_Concurrent(t *testing.T) {
+	var runs int64
+	release := make(chan struct{})
+	l := NewLazyValue(func() (string, error) {
+		atomic.AddInt64(&runs, 1)
+		<-release
+		return "ready", nil
+	})
+
+	var started, wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			if v, err := l.Get(); err != nil || v != "ready" {
+				t.Errorf("l.Get() = %q, %v; want %q, nil", v, err, "ready")
+			}
+		}()
+	}
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt64(&runs); n != 1 {
+		t.Fatalf("Expected init to run once, ran %d times", n)
+	}
+}
+
+// TestLazyValue_RetryOnError tests that a failed init is retried by the next Get.
+func TestLazyValue_RetryOnError(t *testing.T) {
+	boom := errors.New("boom")
+	fail := true
+	l := NewLazyValue(func() (int, error) {
+		if fail {
+			return 0, boom
+		}
+		return 1, nil
+	})
+
+	if _, err := l.Get(); !errors.Is(err, boom) {
+		t.Fatalf("Expected %v, got %v", boom, err)
+	}
+	fail = false
+	if v, err := l.Get(); err != nil || v != 1 {
+		t.Fatalf("l.Get() = %d, %v; want %d, nil", v, err, 1)
+	}
+}
+
+// TestLazyValue_StickyError tests that with retry disabled the first error is kept.
+func TestLazyValue_StickyError(t *testing.T) {
+	boom := errors.New("boom")
+	var runs int64
+	l := NewLazyValue(func() (int, error) {
+		atomic.AddInt64(&runs, 1)
+		return 0, boom
+	})
+	l.RetryOnError(false)
+
+	for i := 0; i < 3; i++ {
+		if _, err := l.Get(); !errors.Is(err, boom) {
+			t.Fatalf("Expected %v, got %v", boom, err)
+		}
+	}
+	if n := atomic.LoadInt64(&runs); n != 1 {
+		t.Fatalf("Expected init to run once, ran %d times", n)
+	}
+}
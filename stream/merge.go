@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge fans in several channels into one. Items are forwarded as they arrive, so the relative order
+// of items from different inputs is not preserved. The returned channel is closed once all inputs are
+// closed.
+func Merge[T any](chans ...<-chan T) <-chan T {
+	return MergeCtx(context.Background(), chans...)
+}
+
+// MergeDistinct fans in several channels like Merge and forwards each distinct item only once, no
+// matter which input delivers it, which suits combining redundant sources of the same events. The
+// returned channel is closed once all inputs are closed. Like Distinct, it remembers every item it has
+// forwarded, so memory grows with the number of distinct items.
+func MergeDistinct[T comparable](chans ...<-chan T) <-chan T {
+	return Distinct(Merge(chans...))
+}
+
+// MergeCtx is like Merge but stops early when ctx is cancelled: it stops reading the inputs, drops any
+// item it was about to forward, and closes the returned channel promptly. No goroutines are left
+// running after the returned channel is closed.
+func MergeCtx[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, ch := range chans {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- item:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(ch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
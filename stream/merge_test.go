@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestMerge tests that all items from all inputs are forwarded and the output closes.
+func TestMerge(t *testing.T) {
+	got := collect(Merge(feed(1, 2, 3), feed(4, 5), feed[int]()))
+	sort.Ints(got)
+
+	want := []int{1, 2, 3, 4, 5}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestMergeDistinct tests that duplicates arriving from different inputs are emitted once and all inputs are drained.
+func TestMergeDistinct(t *testing.T) {
+	a, b := make(chan int, 4), make(chan int, 4)
+	for _, v := range []int{1, 2, 3, 2} {
+		a <- v
+	}
+	for _, v := range []int{3, 4, 1, 4} {
+		b <- v
+	}
+	close(a)
+	close(b)
+
+	got := collect(MergeDistinct[int](a, b))
+	sort.Ints(got)
+	want := []int{1, 2, 3, 4}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+	if len(a) != 0 || len(b) != 0 {
+		t.Errorf("Expected all inputs to be drained, %d and %d items left", len(a), len(b))
+	}
+}
+
+// TestMergeCtx_Cancel tests that cancellation closes the output and leaves no forwarding goroutine running.
+func TestMergeCtx_Cancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := make(chan int) // Never closed.
+	b := make(chan int)
+	out := MergeCtx(ctx, a, b)
+
+	go func() { a <- 1 }()
+	select {
+	case v := <-out:
+		if v != 1 {
+			t.Fatalf("Expected 1, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for item")
+	}
+
+	b2 := make(chan struct{})
+	go func() {
+		defer close(b2)
+		b <- 2 // Blocks the forwarder on the unread output.
+	}()
+	<-b2
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			// The pending item may race with cancellation; the channel must still close.
+			if _, ok := <-out; ok {
+				t.Fatal("Expected output to be closed after cancel")
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected output to close promptly after cancel")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("Expected no leaked goroutines, have %d, started with %d", n, before)
+	}
+}
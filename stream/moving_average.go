@@ -0,0 +1,58 @@
+package stream
+
+// EMA computes the exponential moving average of in and emits the updated average after each
+// input. alpha is the smoothing factor in (0, 1]; higher values weigh recent inputs more. The
+// first input seeds the average. The returned channel is closed once in is closed.
+func EMA(in <-chan float64, alpha float64) <-chan float64 {
+	if alpha <= 0 || alpha > 1 {
+		panic("stream: alpha must be in (0, 1]")
+	}
+
+	out := make(chan float64)
+	go func() {
+		defer close(out)
+
+		var avg float64
+		first := true
+		for v := range in {
+			if first {
+				avg = v
+				first = false
+			} else {
+				avg = alpha*v + (1-alpha)*avg
+			}
+			out <- avg
+		}
+	}()
+	return out
+}
+
+// MovingAverage computes the simple moving average of the last window inputs and emits it after
+// each input. Until window inputs have been seen, the average covers the inputs received so far.
+// The returned channel is closed once in is closed.
+func MovingAverage(in <-chan float64, window int) <-chan float64 {
+	if window <= 0 {
+		panic("stream: window must be greater than zero")
+	}
+
+	out := make(chan float64)
+	go func() {
+		defer close(out)
+
+		ring := make([]float64, window) // Ring buffer of the most recent inputs.
+		var sum float64
+		n, next := 0, 0
+		for v := range in {
+			if n < window {
+				n++
+			} else {
+				sum -= ring[next] // Drop the oldest input leaving the window.
+			}
+			ring[next] = v
+			sum += v
+			next = (next + 1) % window
+			out <- sum / float64(n)
+		}
+	}()
+	return out
+}
@@ -0,0 +1,53 @@
+package stream
+
+import (
+	"math"
+	"testing"
+)
+
+// approxEqual reports whether a and b are equal within a small tolerance.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestEMA tests the exponential moving average against hand-computed values.
+func TestEMA(t *testing.T) {
+	got := collect(EMA(feed(10.0, 20.0, 30.0, 10.0), 0.5))
+	// 10; 0.5*20+0.5*10=15; 0.5*30+0.5*15=22.5; 0.5*10+0.5*22.5=16.25
+	want := []float64{10, 15, 22.5, 16.25}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d values, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !approxEqual(got[i], want[i]) {
+			t.Errorf("EMA[%d] = %v; want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestMovingAverage tests the simple moving average against a known window.
+func TestMovingAverage(t *testing.T) {
+	got := collect(MovingAverage(feed(1.0, 2.0, 3.0, 4.0, 5.0, 6.0), 3))
+	// Partial windows first, then (1+2+3)/3, (2+3+4)/3, ...
+	want := []float64{1, 1.5, 2, 3, 4, 5}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d values, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !approxEqual(got[i], want[i]) {
+			t.Errorf("MovingAverage[%d] = %v; want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestMovingAverage_Empty tests that the output is closed when the input closes without values.
+func TestMovingAverage_Empty(t *testing.T) {
+	if got := collect(MovingAverage(feed[float64](), 3)); len(got) != 0 {
+		t.Errorf("Expected no values, got %v", got)
+	}
+	if got := collect(EMA(feed[float64](), 0.3)); len(got) != 0 {
+		t.Errorf("Expected no values, got %v", got)
+	}
+}
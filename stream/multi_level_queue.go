@@ -0,0 +1,57 @@
+package stream
+
+// MultiLevelQueue is a queue with a small, fixed number of priority levels. Pop always drains higher
+// levels first, and items within a level come out in FIFO order, so producers sharing a level are
+// served fairly. For coarse priorities such as high/normal/low it is cheaper and fairer than a
+// single heap. Like PriorityQueue, it is not safe for concurrent use.
+type MultiLevelQueue[T any] struct {
+	levels [][]T // FIFO per level; level 0 has the highest priority.
+	len    int   // Total number of items across all levels.
+}
+
+// NewMultiLevelQueue creates a new, empty MultiLevelQueue with the given number of levels.
+// Level 0 has the highest priority and level levels-1 the lowest.
+func NewMultiLevelQueue[T any](levels int) *MultiLevelQueue[T] {
+	if levels <= 0 {
+		panic("stream: number of levels must be greater than zero")
+	}
+	return &MultiLevelQueue[T]{
+		levels: make([][]T, levels),
+	}
+}
+
+// Len returns the number of items in the queue.
+func (q *MultiLevelQueue[T]) Len() int {
+	return q.len
+}
+
+// Push appends value to the given level. It panics if level is out of range.
+func (q *MultiLevelQueue[T]) Push(value T, level int) {
+	if level < 0 || level >= len(q.levels) {
+		panic("stream: level out of range")
+	}
+	q.levels[level] = append(q.levels[level], value)
+	q.len++
+}
+
+// Pop removes and returns the oldest item of the highest non-empty level. It returns false if the
+// queue is empty.
+func (q *MultiLevelQueue[T]) Pop() (T, bool) {
+	for i, level := range q.levels {
+		if len(level) == 0 {
+			continue
+		}
+		value := level[0]
+		var zero T
+		level[0] = zero // Release the reference for the garbage collector.
+		if len(level) == 1 {
+			q.levels[i] = level[:0] // Reuse the backing array once the level is drained.
+		} else {
+			q.levels[i] = level[1:]
+		}
+		q.len--
+		return value, true
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMultiLevelQueue_LevelOrder tests that higher levels drain before lower ones.
+func TestMultiLevelQueue_LevelOrder(t *testing.T) {
+	q := NewMultiLevelQueue[string](3)
+	q.Push("low", 2)
+	q.Push("normal", 1)
+	q.Push("high", 0)
+
+	var got []string
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+
+	want := []string{"high", "normal", "low"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestMultiLevelQueue_FIFOWithinLevel tests that items within a level come out in FIFO order.
+func TestMultiLevelQueue_FIFOWithinLevel(t *testing.T) {
+	q := NewMultiLevelQueue[int](2)
+	for i := 1; i <= 3; i++ {
+		q.Push(i, 1)
+		q.Push(i*10, 0)
+	}
+
+	var got []int
+	for {
+		v, ok := q.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, v)
+	}
+
+	want := []int{10, 20, 30, 1, 2, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestMultiLevelQueue_Interleaved tests that a newly pushed higher-level item preempts queued lower-level items.
+func TestMultiLevelQueue_Interleaved(t *testing.T) {
+	q := NewMultiLevelQueue[string](2)
+	q.Push("a", 1)
+	q.Push("b", 1)
+
+	if v, _ := q.Pop(); v != "a" {
+		t.Fatalf("Expected \"a\", got %q", v)
+	}
+	q.Push("urgent", 0)
+	if v, _ := q.Pop(); v != "urgent" {
+		t.Fatalf("Expected \"urgent\", got %q", v)
+	}
+	if v, _ := q.Pop(); v != "b" {
+		t.Fatalf("Expected \"b\", got %q", v)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Expected empty queue")
+	}
+}
+
+// TestMultiLevelQueue_InvalidLevel tests that pushing to an unknown level panics.
+func TestMultiLevelQueue_InvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic for out-of-range level")
+		}
+	}()
+	NewMultiLevelQueue[int](2).Push(1, 2)
+}
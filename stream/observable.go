@@ -0,0 +1,133 @@
+package stream
+
+import "sync"
+
+// Observable holds a single value and notifies subscribers whenever it changes. Each subscriber
+// receives the current value when it subscribes and then later changes, in order. Delivery is
+// conflated per subscriber, like LatestItemQueue: a subscriber that falls behind skips intermediate
+// values and receives only the latest one it has not seen, so a slow subscriber never blocks Set or
+// other subscribers and holds at most one undelivered value. It is safe for concurrent use by
+// multiple goroutines.
+type Observable[T any] struct {
+	value T                             // Current value.
+	equal func(a, b T) bool             // Suppresses notification of an unchanged value; nil to always notify.
+	subs  map[<-chan T]*subscription[T] // Active subscriptions by their channel.
+	mu    sync.Mutex                    // Mutex to protect value and subs.
+}
+
+// subscription delivers the values published to one subscriber. The publisher stores each value in
+// latest, replacing any value not yet delivered, and a dedicated goroutine forwards it to out.
+type subscription[T any] struct {
+	out     chan T        // Channel returned to the subscriber; closed once the subscription ends.
+	latest  T             // Latest value not yet delivered; valid if pending is set.
+	pending bool          // Whether latest holds a value to deliver.
+	wake    chan struct{} // Signals the forwarding goroutine that a value is pending.
+	done    chan struct{} // Closed to end the subscription.
+	mu      sync.Mutex    // Mutex to protect latest and pending.
+}
+
+// NewObservable creates a new Observable holding initial. If equal is not nil, Set does not notify
+// subscribers when equal reports the new value equal to the current one.
+func NewObservable[T any](initial T, equal func(a, b T) bool) *Observable[T] {
+	return &Observable[T]{
+		value: initial,
+		equal: equal,
+		subs:  make(map[<-chan T]*subscription[T]),
+	}
+}
+
+// Get returns the current value.
+func (o *Observable[T]) Get() T {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.value
+}
+
+// Set replaces the current value and publishes it to all subscribers, unless the Observable was
+// created with an equality function that reports it unchanged.
+func (o *Observable[T]) Set(value T) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.equal != nil && o.equal(o.value, value) {
+		return
+	}
+	o.value = value
+	for _, s := range o.subs {
+		s.publish(value)
+	}
+}
+
+// Subscribe returns a channel that receives the current value immediately and every later change.
+// The channel is closed by Unsubscribe.
+func (o *Observable[T]) Subscribe() <-chan T {
+	s := &subscription[T]{
+		out:  make(chan T),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go s.run()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.subs[s.out] = s
+	s.publish(o.value)
+	return s.out
+}
+
+// Unsubscribe ends the subscription for ch, which is closed once any delivery in progress has been
+// abandoned. Values not yet received are dropped. It reports false if ch is not an active
+// subscription.
+func (o *Observable[T]) Unsubscribe(ch <-chan T) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	s, ok := o.subs[ch]
+	if ok {
+		delete(o.subs, ch)
+		close(s.done)
+	}
+	return ok
+}
+
+// publish queues value for delivery, replacing a value not yet delivered.
+func (s *subscription[T]) publish(value T) {
+	s.mu.Lock()
+	s.latest, s.pending = value, true
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wake-up is already pending.
+	}
+}
+
+// run forwards pending values to out until the subscription ends.
+func (s *subscription[T]) run() {
+	defer close(s.out)
+
+	for {
+		select {
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+
+		s.mu.Lock()
+		value, ok := s.latest, s.pending
+		var zero T
+		s.latest, s.pending = zero, false // Do not keep the value reachable.
+		s.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		select {
+		case s.out <- value:
+		case <-s.done:
+			return
+		}
+	}
+}
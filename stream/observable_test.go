@@ -0,0 +1,115 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+// receive returns the next value from ch, failing the test if none arrives in time.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a value")
+		panic("unreachable")
+	}
+}
+
+// TestObservable_Subscribe tests that a subscriber keeping up receives the current value and every later change.
+func TestObservable_Subscribe(t *testing.T) {
+	o := NewObservable(1, nil)
+	ch := o.Subscribe()
+	if got := receive(t, ch); got != 1 {
+		t.Fatalf("Expected %d, got %d", 1, got)
+	}
+
+	for _, want := range []int{2, 3, 3} {
+		o.Set(want)
+		if got := receive(t, ch); got != want {
+			t.Fatalf("Expected %d, got %d", want, got)
+		}
+	}
+	if v := o.Get(); v != 3 {
+		t.Fatalf("o.Get() = %d; want %d", v, 3)
+	}
+}
+
+// TestObservable_SuppressUnchanged tests that an equality function suppresses notification of an unchanged value.
+func TestObservable_SuppressUnchanged(t *testing.T) {
+	o := NewObservable("a", func(a, b string) bool { return a == b })
+	ch := o.Subscribe()
+	if got := receive(t, ch); got != "a" {
+		t.Fatalf("Expected %q, got %q", "a", got)
+	}
+
+	o.Set("a")
+	o.Set("b")
+	if got := receive(t, ch); got != "b" {
+		t.Fatalf("Expected %q, got %q", "b", got)
+	}
+	o.Set("b")
+	o.Set("c")
+	if got := receive(t, ch); got != "c" {
+		t.Fatalf("Expected %q, got %q", "c", got)
+	}
+	select {
+	case v := <-ch:
+		t.Fatalf("Expected no further notification, got %q", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// TestObservable_Unsubscribe tests that Unsubscribe closes the channel and stops delivery.
+func TestObservable_Unsubscribe(t *testing.T) {
+	o := NewObservable(0, nil)
+	ch := o.Subscribe()
+	other := o.Subscribe()
+	receive(t, ch)
+	receive(t, other)
+
+	if !o.Unsubscribe(ch) {
+		t.Fatal("Expected Unsubscribe to succeed")
+	}
+	if o.Unsubscribe(ch) {
+		t.Fatal("Expected a second Unsubscribe to fail")
+	}
+	o.Set(1)
+
+	for range ch { // Drains until closed.
+	}
+	if got := receive(t, other); got != 1 {
+		t.Fatalf("Expected the remaining subscriber to get %d, got %d", 1, got)
+	}
+}
+
+// TestObservable_StalledSubscriber tests that a subscriber that stops receiving holds only the latest value and does not block Set.
+func TestObservable_StalledSubscriber(t *testing.T) {
+	o := NewObservable(0, nil)
+	stalled := o.Subscribe()
+	other := o.Subscribe()
+	receive(t, stalled)
+	receive(t, other)
+
+	for i := 1; i <= 1000; i++ {
+		o.Set(i)
+		if got := receive(t, other); got != i {
+			t.Fatalf("Expected the other subscriber to get %d, got %d", i, got)
+		}
+	}
+
+	// At most the value being delivered when the subscriber stalled precedes the latest one.
+	var got []int
+	for len(got) == 0 || got[len(got)-1] != 1000 {
+		got = append(got, receive(t, stalled))
+	}
+	if len(got) > 2 {
+		t.Fatalf("Expected at most 2 values after stalling, got %d", len(got))
+	}
+	select {
+	case v := <-stalled:
+		t.Fatalf("Expected no further value, got %d", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
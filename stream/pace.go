@@ -0,0 +1,31 @@
+package stream
+
+import "time"
+
+// Pace forwards items from in while ensuring at least minInterval elapses between consecutive
+// emissions, regardless of how fast items arrive. Items that arrive too early wait their turn, so
+// order is preserved and nothing is dropped; as a result, in is not read while an item is waiting.
+// The first item is emitted immediately. When in is closed, the returned channel is closed after the
+// last item has been emitted.
+func Pace[T any](in <-chan T, minInterval time.Duration) <-chan T {
+	if minInterval < 0 {
+		panic("stream: minInterval must not be negative")
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		var last time.Time
+		for item := range in {
+			if !last.IsZero() {
+				if wait := minInterval - time.Since(last); wait > 0 {
+					time.Sleep(wait)
+				}
+			}
+			out <- item
+			last = time.Now()
+		}
+	}()
+	return out
+}
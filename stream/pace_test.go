@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPace_Burst tests that a burst is emitted at the paced interval with order preserved.
+func TestPace_Burst(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	out := Pace(feed(1, 2, 3, 4, 5), interval)
+
+	var times []time.Time
+	next := 1
+	for item := range out {
+		if item != next {
+			t.Fatalf("Expected %d, got %d", next, item)
+		}
+		next++
+		times = append(times, time.Now())
+	}
+
+	if len(times) != 5 {
+		t.Fatalf("Expected 5 items, got %d", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < interval-2*time.Millisecond {
+			t.Errorf("Gap between emissions %d and %d was %v; want at least %v", i-1, i, gap, interval)
+		}
+	}
+	if total := times[4].Sub(times[0]); total > 4*interval+200*time.Millisecond {
+		t.Errorf("Expected the burst to take about %v, took %v", 4*interval, total)
+	}
+}
+
+// TestPace_SlowInput tests that items arriving slower than the interval are not delayed further.
+func TestPace_SlowInput(t *testing.T) {
+	in := make(chan int)
+	out := Pace(in, 10*time.Millisecond)
+
+	go func() {
+		defer close(in)
+		for i := 0; i < 3; i++ {
+			in <- i
+			time.Sleep(30 * time.Millisecond)
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		<-out
+		if i > 0 {
+			if waited := time.Since(start); waited > 60*time.Millisecond {
+				t.Errorf("Item %d was delayed %v beyond its arrival", i, waited)
+			}
+		}
+	}
+	if _, ok := <-out; ok {
+		t.Fatal("Expected output to be closed")
+	}
+}
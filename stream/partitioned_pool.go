@@ -0,0 +1,82 @@
+package stream
+
+import "sync"
+
+// PartitionedPool runs tasks on a bounded number of workers while keeping tasks ordered per partition
+// key: tasks submitted with the same key run one at a time in submission order, and tasks with
+// different keys run concurrently, up to the worker count. This is the ordering guarantee of a
+// Kafka-style consumer. Tasks are queued without bound, so Submit never blocks.
+type PartitionedPool[K comparable] struct {
+	mu     sync.Mutex     // Mutex to protect queues and closed.
+	queues map[K][]func() // Pending tasks of each partition that has a runner.
+	sem    chan struct{}  // Worker slots.
+	wg     sync.WaitGroup // Tracks submitted tasks that have not finished.
+	closed bool           // Set by Close; further submissions panic.
+}
+
+// NewPartitionedPool creates a new PartitionedPool that runs at most workers tasks at a time.
+func NewPartitionedPool[K comparable](workers int) *PartitionedPool[K] {
+	if workers <= 0 {
+		panic("stream: number of workers must be greater than zero")
+	}
+	return &PartitionedPool[K]{
+		queues: make(map[K][]func()),
+		sem:    make(chan struct{}, workers),
+	}
+}
+
+// Submit queues task under the partition key. It panics if the pool has been closed.
+func (p *PartitionedPool[K]) Submit(key K, task func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		panic("stream: submit on closed pool")
+	}
+	p.wg.Add(1)
+
+	if queue, running := p.queues[key]; running {
+		p.queues[key] = append(queue, task)
+		return
+	}
+	p.queues[key] = []func(){task}
+	go p.run(key)
+}
+
+// Wait blocks until all submitted tasks have finished.
+func (p *PartitionedPool[K]) Wait() {
+	p.wg.Wait()
+}
+
+// Close stops accepting new tasks and waits for all submitted tasks to finish.
+func (p *PartitionedPool[K]) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// run drains the queue of a single partition on one worker slot. Exactly one runner exists per
+// partition with pending tasks, which serializes the partition's tasks.
+func (p *PartitionedPool[K]) run(key K) {
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+
+	for {
+		p.mu.Lock()
+		queue := p.queues[key]
+		if len(queue) == 0 {
+			delete(p.queues, key) // The next Submit for key starts a new runner.
+			p.mu.Unlock()
+			return
+		}
+		task := queue[0]
+		queue[0] = nil
+		p.queues[key] = queue[1:]
+		p.mu.Unlock()
+
+		task()
+		p.wg.Done()
+	}
+}
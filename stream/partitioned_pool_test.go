@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPartitionedPool_SameKeyOrdered tests that same-key tasks never overlap and run in submission order.
+func TestPartitionedPool_SameKeyOrdered(t *testing.T) {
+	pool := NewPartitionedPool[string](4)
+
+	var mu sync.Mutex
+	order := make(map[string][]int)
+	running := make(map[string]*int32)
+	for _, key := range []string{"a", "b", "c"} {
+		running[key] = new(int32)
+	}
+
+	for i := 0; i < 50; i++ {
+		for key, active := range running {
+			key, active, i := key, active, i
+			pool.Submit(key, func() {
+				if atomic.AddInt32(active, 1) != 1 {
+					t.Errorf("Tasks for key %q overlapped", key)
+				}
+				time.Sleep(100 * time.Microsecond)
+				mu.Lock()
+				order[key] = append(order[key], i)
+				mu.Unlock()
+				atomic.AddInt32(active, -1)
+			})
+		}
+	}
+	pool.Close()
+
+	for key, seq := range order {
+		if len(seq) != 50 {
+			t.Fatalf("Expected 50 tasks for key %q, got %d", key, len(seq))
+		}
+		for i, v := range seq {
+			if v != i {
+				t.Fatalf("Tasks for key %q ran out of order: %v", key, seq)
+			}
+		}
+	}
+}
+
+// TestPartitionedPool_DifferentKeysParallel tests that different-key tasks run concurrently up to the worker count.
+func TestPartitionedPool_DifferentKeysParallel(t *testing.T) {
+	const workers = 3
+	pool := NewPartitionedPool[int](workers)
+
+	var active, peak int32
+	release := make(chan struct{})
+	for key := 0; key < 6; key++ {
+		pool.Submit(key, func() {
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&active, -1)
+		})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&active) < workers && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	pool.Wait()
+
+	if peak != workers {
+		t.Fatalf("Expected %d tasks to run in parallel, observed %d", workers, peak)
+	}
+}
+
+// TestPartitionedPool_SubmitAfterClose tests that submitting to a closed pool panics.
+func TestPartitionedPool_SubmitAfterClose(t *testing.T) {
+	pool := NewPartitionedPool[int](1)
+	pool.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic when submitting to a closed pool")
+		}
+	}()
+	pool.Submit(1, func() {})
+}
@@ -1,6 +1,9 @@
 package stream
 
-import "container/heap"
+import (
+	"container/heap"
+	"fmt"
+)
 
 // Item holds the details of the queue item, including its value, priority, and index in the queue.
 type Item[T any] struct {
@@ -58,10 +61,125 @@ func (pq *PriorityQueue[T]) Push(x any) {
 }
 
 // Update modifies the priority and value of an Item in the queue and adjusts the queue to maintain heap invariant.
-func (pq *PriorityQueue[T]) Update(item *Item[T], value T, priority int) {
+// It reports false and leaves both the item and the queue untouched if the item is not in the queue, for example
+// because it has already been popped.
+func (pq *PriorityQueue[T]) Update(item *Item[T], value T, priority int) bool {
+	if item.index < 0 || item.index >= len(*pq) || (*pq)[item.index] != item {
+		return false
+	}
 	item.value = value
 	item.priority = priority
 	heap.Fix(pq, item.index)
+	return true
+}
+
+// RemoveFunc removes every item for which match reports true and returns the number removed. The
+// remaining items are compacted in place and the heap is rebuilt once with heap.Init, which is O(n)
+// regardless of how many items match. Removed items are marked as no longer in the queue, so Update
+// rejects them.
+func (pq *PriorityQueue[T]) RemoveFunc(match func(*Item[T]) bool) int {
+	old := *pq
+	kept := old[:0]
+	for _, item := range old {
+		if match(item) {
+			item.index = -1 // Mark as removed
+			continue
+		}
+		item.index = len(kept)
+		kept = append(kept, item)
+	}
+	for i := len(kept); i < len(old); i++ {
+		old[i] = nil // Release the reference for the garbage collector.
+	}
+
+	removed := len(old) - len(kept)
+	*pq = kept
+	if removed > 0 {
+		heap.Init(pq)
+	}
+	return removed
+}
+
+// Peek returns the highest priority item without removing it, or nil if the queue is empty. Neither
+// the queue nor the item's index is modified.
+func (pq PriorityQueue[T]) Peek() *Item[T] {
+	if len(pq) == 0 {
+		return nil
+	}
+	return pq[0]
+}
+
+// PeekValue returns the value of the highest priority item without removing it. It returns false if
+// the queue is empty.
+func (pq PriorityQueue[T]) PeekValue() (T, bool) {
+	if item := pq.Peek(); item != nil {
+		return item.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+// TopN returns up to n of the highest priority items in priority order without removing them. It pops
+// from a copy of the heap that holds positions into the queue, so neither the queue nor the items'
+// indexes are modified. It runs in O(len + n log len) time.
+func (pq *PriorityQueue[T]) TopN(n int) []*Item[T] {
+	if n > pq.Len() {
+		n = pq.Len()
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	shadow := &positionHeap[T]{pq: *pq, pos: make([]int, pq.Len())}
+	for i := range shadow.pos {
+		shadow.pos[i] = i // The queue is already a heap, so the identity copy is one too.
+	}
+
+	top := make([]*Item[T], 0, n)
+	for len(top) < n {
+		top = append(top, (*pq)[heap.Pop(shadow).(int)])
+	}
+	return top
+}
+
+// positionHeap is a heap of positions into a PriorityQueue, ordered like the queue itself. It lets
+// TopN pop from a copy of the heap without touching the queue's items. It implements heap.Interface.
+type positionHeap[T any] struct {
+	pq  PriorityQueue[T]
+	pos []int
+}
+
+func (h *positionHeap[T]) Len() int           { return len(h.pos) }
+func (h *positionHeap[T]) Less(i, j int) bool { return h.pq.Less(h.pos[i], h.pos[j]) }
+func (h *positionHeap[T]) Swap(i, j int)      { h.pos[i], h.pos[j] = h.pos[j], h.pos[i] }
+func (h *positionHeap[T]) Push(x any)         { h.pos = append(h.pos, x.(int)) }
+
+func (h *positionHeap[T]) Pop() any {
+	n := len(h.pos)
+	p := h.pos[n-1]
+	h.pos = h.pos[:n-1]
+	return p
+}
+
+// validate checks that the heap invariant holds and that every item's index matches its position
+// in the queue. It returns a descriptive error for the first violation found and is intended for
+// tests and debugging.
+func (pq *PriorityQueue[T]) validate() error {
+	for i, item := range *pq {
+		if item == nil {
+			return fmt.Errorf("stream: nil item at position %d", i)
+		}
+		if item.index != i {
+			return fmt.Errorf("stream: item at position %d has index %d", i, item.index)
+		}
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < pq.Len() && pq.Less(child, i) {
+				return fmt.Errorf("stream: heap invariant violated: child %d (priority %d) ranks above parent %d (priority %d)",
+					child, (*pq)[child].priority, i, item.priority)
+			}
+		}
+	}
+	return nil
 }
 
 // Ensure PriorityQueue implements heap.Interface at compile time.
@@ -0,0 +1,80 @@
+package stream
+
+import "container/heap"
+
+// PriorityQueueFunc is a priority queue ordered by a caller-supplied comparison function rather than
+// an integer priority. The item for which less reports true against every other item is retrieved
+// first, so passing a "less than" function yields a min-heap and a "greater than" function a max-heap.
+// Like PriorityQueue, it implements heap.Interface and is used through the container/heap functions.
+type PriorityQueueFunc[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueueFunc creates a new, empty PriorityQueueFunc ordered by less.
+func NewPriorityQueueFunc[T any](less func(a, b T) bool) *PriorityQueueFunc[T] {
+	if less == nil {
+		panic("stream: less function must not be nil")
+	}
+	return &PriorityQueueFunc[T]{less: less}
+}
+
+// Len returns the number of elements in the priority queue. It is part of heap.Interface.
+func (pq *PriorityQueueFunc[T]) Len() int {
+	return len(pq.items)
+}
+
+// Less reports whether the item at index i should be retrieved before the item at index j. It is part
+// of heap.Interface.
+func (pq *PriorityQueueFunc[T]) Less(i, j int) bool {
+	return pq.less(pq.items[i], pq.items[j])
+}
+
+// Swap swaps the elements at indexes i and j. It is part of heap.Interface.
+func (pq *PriorityQueueFunc[T]) Swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+}
+
+// Push adds an item to the end of the queue. It is part of heap.Interface; use heap.Push to maintain
+// the heap invariant.
+func (pq *PriorityQueueFunc[T]) Push(x any) {
+	pq.items = append(pq.items, x.(T))
+}
+
+// Pop removes and returns the last item of the queue. It is part of heap.Interface; use heap.Pop to
+// retrieve the first item in priority order.
+func (pq *PriorityQueueFunc[T]) Pop() any {
+	n := len(pq.items)
+	item := pq.items[n-1]
+	var zero T
+	pq.items[n-1] = zero // Release the reference for the garbage collector.
+	pq.items = pq.items[:n-1]
+	return item
+}
+
+// Update replaces the first queued item for which match reports true with value and moves it to its
+// place under the queue's ordering with heap.Fix. It reports false and leaves the queue untouched if
+// no item matches. Finding the item is O(n) in the number of queued items.
+func (pq *PriorityQueueFunc[T]) Update(match func(item T) bool, value T) bool {
+	for i, item := range pq.items {
+		if match(item) {
+			pq.items[i] = value
+			heap.Fix(pq, i)
+			return true
+		}
+	}
+	return false
+}
+
+// SetLess replaces the comparison function and re-establishes the heap invariant under the new
+// ordering. This is O(n) in the number of queued items.
+func (pq *PriorityQueueFunc[T]) SetLess(less func(a, b T) bool) {
+	if less == nil {
+		panic("stream: less function must not be nil")
+	}
+	pq.less = less
+	heap.Init(pq)
+}
+
+// Ensure PriorityQueueFunc implements heap.Interface at compile time.
+var _ heap.Interface = (*PriorityQueueFunc[string])(nil)
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"container/heap"
+	"testing"
+)
+
+// job is a test item that can be ordered by deadline or by cost.
+type job struct {
+	name     string
+	deadline int
+	cost     int
+}
+
+func byDeadline(a, b job) bool { return a.deadline < b.deadline }
+func byCost(a, b job) bool     { return a.cost < b.cost }
+
+// TestPriorityQueueFunc_PushPop tests that items are popped in comparator order.
+func TestPriorityQueueFunc_PushPop(t *testing.T) {
+	pq := NewPriorityQueueFunc(func(a, b int) bool { return a < b }) // Min-heap.
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		heap.Push(pq, v)
+	}
+
+	for want := 1; want <= 5; want++ {
+		if got := heap.Pop(pq).(int); got != want {
+			t.Errorf("Expected %d, got %d", want, got)
+		}
+	}
+	if pq.Len() != 0 {
+		t.Errorf("Expected empty queue, got length %d", pq.Len())
+	}
+}
+
+// TestPriorityQueueFunc_SetLess tests that after SetLess the pop order reflects the new comparator.
+func TestPriorityQueueFunc_SetLess(t *testing.T) {
+	pq := NewPriorityQueueFunc(byDeadline)
+	heap.Push(pq, job{name: "a", deadline: 1, cost: 30})
+	heap.Push(pq, job{name: "b", deadline: 2, cost: 10})
+	heap.Push(pq, job{name: "c", deadline: 3, cost: 20})
+
+	if first := heap.Pop(pq).(job); first.name != "a" {
+		t.Fatalf("Expected \"a\" first by deadline, got %q", first.name)
+	}
+	heap.Push(pq, job{name: "a", deadline: 1, cost: 30})
+
+	pq.SetLess(byCost)
+	for _, want := range []string{"b", "c", "a"} {
+		if got := heap.Pop(pq).(job); got.name != want {
+			t.Errorf("Expected %q by cost, got %q", want, got.name)
+		}
+	}
+}
+
+// TestPriorityQueueFunc_UpdateMinHeap tests that Update reorders a min-heap of ints.
+func TestPriorityQueueFunc_UpdateMinHeap(t *testing.T) {
+	pq := NewPriorityQueueFunc(func(a, b int) bool { return a < b })
+	for _, v := range []int{10, 20, 30} {
+		heap.Push(pq, v)
+	}
+
+	if !pq.Update(func(v int) bool { return v == 30 }, 5) {
+		t.Fatal("Expected updating 30 to succeed")
+	}
+	if pq.Update(func(v int) bool { return v == 99 }, 1) {
+		t.Fatal("Expected updating a missing item to fail")
+	}
+
+	for _, want := range []int{5, 10, 20} {
+		if got := heap.Pop(pq).(int); got != want {
+			t.Errorf("Expected %d, got %d", want, got)
+		}
+	}
+}
+
+// TestPriorityQueueFunc_UpdateStructField tests that Update reorders items compared by a struct field.
+func TestPriorityQueueFunc_UpdateStructField(t *testing.T) {
+	pq := NewPriorityQueueFunc(byDeadline)
+	heap.Push(pq, job{name: "a", deadline: 1})
+	heap.Push(pq, job{name: "b", deadline: 2})
+	heap.Push(pq, job{name: "c", deadline: 3})
+
+	pq.Update(func(j job) bool { return j.name == "a" }, job{name: "a", deadline: 4})
+
+	for _, want := range []string{"b", "c", "a"} {
+		if got := heap.Pop(pq).(job); got.name != want {
+			t.Errorf("Expected %q by deadline, got %q", want, got.name)
+		}
+	}
+}
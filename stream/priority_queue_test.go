@@ -90,3 +90,168 @@ func TestPriorityQueue_MixedOperations(t *testing.T) {
 		t.Errorf("Expected value of 3 and priority of 10 for first popped item, got value %d and priority %d", firstItem.value, firstItem.priority)
 	}
 }
+
+// TestPriorityQueue_Validate tests that a correctly built heap validates and a corrupted one does not.
+func TestPriorityQueue_Validate(t *testing.T) {
+	pq := NewPriorityQueue[int]()
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected empty queue to validate, got %v", err)
+	}
+
+	for i, p := range []int{5, 1, 8, 3, 9, 2} {
+		heap.Push(pq, &Item[int]{value: i, priority: p})
+	}
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected heap to validate, got %v", err)
+	}
+
+	// Corrupt the index of an item.
+	(*pq)[1].index = 4
+	if err := pq.validate(); err == nil {
+		t.Error("Expected error for stale item index")
+	}
+	(*pq)[1].index = 1
+
+	// Corrupt the heap order by lowering the root's priority without fixing the heap.
+	(*pq)[0].priority = -1
+	if err := pq.validate(); err == nil {
+		t.Error("Expected error for heap invariant violation")
+	}
+	heap.Fix(pq, 0)
+	if err := pq.validate(); err != nil {
+		t.Errorf("Expected heap to validate after Fix, got %v", err)
+	}
+}
+
+// TestPriorityQueue_UpdateStale tests that updating a popped item is rejected without corrupting the heap.
+func TestPriorityQueue_UpdateStale(t *testing.T) {
+	pq := NewPriorityQueue[int]()
+	heap.Init(pq)
+	heap.Push(pq, &Item[int]{value: 1, priority: 1})
+	heap.Push(pq, &Item[int]{value: 2, priority: 2})
+	heap.Push(pq, &Item[int]{value: 3, priority: 3})
+
+	popped := heap.Pop(pq).(*Item[int])
+	if ok := pq.Update(popped, 30, 30); ok {
+		t.Fatal("Expected Update on a popped item to report false")
+	}
+	if popped.value != 3 || popped.priority != 3 {
+		t.Errorf("Expected popped item to be left untouched, got value %d and priority %d", popped.value, popped.priority)
+	}
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected heap to remain valid, got %v", err)
+	}
+
+	// An item whose index points at another item's slot is rejected as well.
+	foreign := &Item[int]{value: 9, priority: 9, index: 0}
+	if ok := pq.Update(foreign, 9, 99); ok {
+		t.Fatal("Expected Update on an item not in the queue to report false")
+	}
+	if top := heap.Pop(pq).(*Item[int]); top.priority != 2 {
+		t.Errorf("Expected priority 2 on top, got %d", top.priority)
+	}
+}
+
+// TestPriorityQueue_Peek tests that Peek returns the highest priority item without modifying the queue.
+func TestPriorityQueue_Peek(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	if item := pq.Peek(); item != nil {
+		t.Fatalf("Expected nil from an empty queue, got %+v", item)
+	}
+	if _, ok := pq.PeekValue(); ok {
+		t.Fatal("Expected PeekValue to report an empty queue")
+	}
+
+	heap.Push(pq, &Item[string]{value: "low", priority: 1})
+	heap.Push(pq, &Item[string]{value: "high", priority: 3})
+	heap.Push(pq, &Item[string]{value: "mid", priority: 2})
+
+	top := pq.Peek()
+	if top == nil || top.value != "high" || top.index != 0 {
+		t.Fatalf("Expected \"high\" at index 0, got %+v", top)
+	}
+	if v, ok := pq.PeekValue(); !ok || v != "high" {
+		t.Fatalf("pq.PeekValue() = %q, %v; want %q, %v", v, ok, "high", true)
+	}
+	if pq.Len() != 3 {
+		t.Fatalf("Expected Peek not to remove items, got length %d", pq.Len())
+	}
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected a valid heap after Peek, got %v", err)
+	}
+	if item := heap.Pop(pq).(*Item[string]); item != top {
+		t.Fatalf("Expected Pop to return the peeked item, got %+v", item)
+	}
+}
+
+// TestPriorityQueue_RemoveFunc tests that matching items are removed and the rest still pop in order.
+func TestPriorityQueue_RemoveFunc(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	var removedItem *Item[string]
+	for i, v := range []string{"a1", "b1", "a2", "b2", "a3", "b3"} {
+		item := &Item[string]{value: v, priority: i}
+		if v == "a2" {
+			removedItem = item
+		}
+		heap.Push(pq, item)
+	}
+
+	n := pq.RemoveFunc(func(item *Item[string]) bool { return item.value[0] == 'a' })
+	if n != 3 {
+		t.Fatalf("Expected 3 items removed, got %d", n)
+	}
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected a valid heap after RemoveFunc, got %v", err)
+	}
+	if pq.Update(removedItem, "a2", 100) {
+		t.Fatal("Expected Update of a removed item to fail")
+	}
+
+	for _, want := range []string{"b3", "b2", "b1"} {
+		if got := heap.Pop(pq).(*Item[string]).value; got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+	if n := pq.RemoveFunc(func(*Item[string]) bool { return true }); n != 0 {
+		t.Fatalf("Expected nothing to remove from an empty queue, got %d", n)
+	}
+}
+
+// TestPriorityQueue_TopN tests that TopN returns the highest items in order and leaves the queue intact.
+func TestPriorityQueue_TopN(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	heap.Init(pq)
+	for i, p := range []int{4, 9, 1, 7, 3, 8, 2} {
+		heap.Push(pq, &Item[string]{value: string(rune('a' + i)), priority: p})
+	}
+	before := append(PriorityQueue[string](nil), *pq...)
+
+	top := pq.TopN(3)
+	if len(top) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(top))
+	}
+	for i, want := range []int{9, 8, 7} {
+		if top[i].priority != want {
+			t.Errorf("TopN[%d] priority = %d; want %d", i, top[i].priority, want)
+		}
+	}
+
+	if pq.Len() != len(before) {
+		t.Fatalf("Expected queue length %d, got %d", len(before), pq.Len())
+	}
+	for i := range before {
+		if (*pq)[i] != before[i] {
+			t.Fatalf("Expected queue order to be unchanged at position %d", i)
+		}
+	}
+	if err := pq.validate(); err != nil {
+		t.Fatalf("Expected queue to remain valid, got %v", err)
+	}
+
+	if all := pq.TopN(100); len(all) != pq.Len() || all[len(all)-1].priority != 1 {
+		t.Errorf("Expected TopN beyond length to return all items in order, got %d items", len(all))
+	}
+	if none := pq.TopN(0); len(none) != 0 {
+		t.Errorf("Expected no items for n = 0, got %d", len(none))
+	}
+}
@@ -0,0 +1,43 @@
+package stream
+
+import "time"
+
+// RateMeter measures the throughput of a stream. Items from in are forwarded unchanged on
+// passthrough, and every interval the observed rate in events per second since the previous reading
+// is emitted on rate. rate holds only the latest reading, like a LatestItemQueue, so it never blocks
+// the passthrough when nobody reads it. Both channels are closed once in is closed.
+func RateMeter[T any](in <-chan T, interval time.Duration) (passthrough <-chan T, rate <-chan float64) {
+	if interval <= 0 {
+		panic("stream: interval must be greater than zero")
+	}
+
+	out := make(chan T)
+	rates := NewLatestItemQueue[float64]()
+	go func() {
+		defer close(out)
+		defer rates.Close()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		count := 0
+		last := time.Now()
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				out <- item
+				count++
+			case now := <-ticker.C:
+				if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+					rates.Produce(float64(count) / elapsed)
+				}
+				count = 0
+				last = now
+			}
+		}
+	}()
+	return out, rates.ConsumeChannel()
+}
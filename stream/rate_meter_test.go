@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRateMeter_SteadyRate tests that a steady input rate produces a reading near the expected value.
+func TestRateMeter_SteadyRate(t *testing.T) {
+	in := make(chan int)
+	passthrough, rate := RateMeter(in, 200*time.Millisecond)
+
+	// Produce roughly 200 events per second.
+	go func() {
+		defer close(in)
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; i < 200; i++ {
+			<-ticker.C
+			in <- i
+		}
+	}()
+
+	// Drain the passthrough, checking that items are forwarded unchanged and in order.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		next := 0
+		for item := range passthrough {
+			if item != next {
+				t.Errorf("Expected %d, got %d", next, item)
+			}
+			next++
+		}
+	}()
+
+	select {
+	case <-rate: // The first reading may cover the warm-up.
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for first rate reading")
+	}
+	select {
+	case r := <-rate:
+		if r < 100 || r > 300 {
+			t.Errorf("Expected a rate near 200/s, got %.1f/s", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for rate reading")
+	}
+
+	<-done
+	if _, ok := <-rate; ok {
+		// A final reading may still be buffered; the channel must close after it.
+		if _, ok := <-rate; ok {
+			t.Fatal("Expected rate channel to be closed")
+		}
+	}
+}
+
+// TestRateMeter_UnreadRateDoesNotBlock tests that the passthrough keeps flowing when nobody reads rate.
+func TestRateMeter_UnreadRateDoesNotBlock(t *testing.T) {
+	in := make(chan int)
+	passthrough, _ := RateMeter(in, time.Millisecond)
+
+	go func() {
+		defer close(in)
+		for i := 0; i < 50; i++ {
+			in <- i
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	count := 0
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-passthrough:
+			if !ok {
+				if count != 50 {
+					t.Fatalf("Expected 50 items, got %d", count)
+				}
+				return
+			}
+			count++
+		case <-timeout:
+			t.Fatalf("Passthrough blocked after %d items", count)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"container/heap"
+	"time"
+)
+
+// stamped is an item buffered by Reorder together with its timestamp.
+type stamped[T any] struct {
+	ts    int64
+	value T
+}
+
+// Reorder re-sorts a slightly out-of-order stream. keyFn returns each item's timestamp in
+// nanoseconds, for example from time.Time.UnixNano. Items are buffered in a PriorityQueueFunc and
+// emitted in timestamp order once they are older than window relative to the newest timestamp seen
+// so far, since no earlier item is expected to arrive after that point. When in is closed, the
+// remaining items are emitted in order and the returned channel is closed.
+//
+// An item that arrives later than the window allows, i.e. with a timestamp before the last emitted
+// one, cannot be placed in order anymore and is forwarded immediately rather than dropped.
+func Reorder[T any](in <-chan T, keyFn func(T) int64, window time.Duration) <-chan T {
+	if window < 0 {
+		panic("stream: window must not be negative")
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		pq := NewPriorityQueueFunc(func(a, b stamped[T]) bool { return a.ts < b.ts })
+		var newest, emitted int64
+		started := false
+
+		for item := range in {
+			ts := keyFn(item)
+			if started && ts < emitted {
+				out <- item // Too late to reorder.
+				continue
+			}
+			if !started || ts > newest {
+				newest = ts
+				started = true
+			}
+
+			heap.Push(pq, stamped[T]{ts: ts, value: item})
+
+			watermark := newest - int64(window)
+			for pq.Len() > 0 && pq.items[0].ts <= watermark {
+				next := heap.Pop(pq).(stamped[T])
+				emitted = next.ts
+				out <- next.value
+			}
+		}
+
+		for pq.Len() > 0 {
+			out <- heap.Pop(pq).(stamped[T]).value
+		}
+	}()
+	return out
+}
@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// identity returns its argument as a reorder key.
+func identity(v int64) int64 { return v }
+
+// TestReorder_WithinWindow tests that out-of-order input within the window is emitted sorted.
+func TestReorder_WithinWindow(t *testing.T) {
+	in := feed[int64](3, 1, 2, 5, 4, 7, 6)
+	got := collect(Reorder(in, identity, 3*time.Nanosecond))
+	want := []int64{1, 2, 3, 4, 5, 6, 7}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestReorder_WideTimestamps tests that timestamps beyond 32 bits, such as UnixNano values, are ordered correctly on every platform.
+func TestReorder_WideTimestamps(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	in := feed[int64](base+3<<32, base+1<<32, base+2<<32, base)
+	got := collect(Reorder(in, identity, time.Hour))
+	want := []int64{base, base + 1<<32, base + 2<<32, base + 3<<32}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestReorder_EmitsBeforeClose tests that items older than the window are emitted without waiting for close.
+func TestReorder_EmitsBeforeClose(t *testing.T) {
+	in := make(chan int64)
+	out := Reorder(in, identity, 10*time.Nanosecond)
+
+	in <- 5
+	in <- 2
+	go func() { in <- 20 }() // Advances the watermark to 10, releasing 2 and 5.
+
+	for _, want := range []int64{2, 5} {
+		select {
+		case got := <-out:
+			if got != want {
+				t.Fatalf("Expected %d, got %d", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for item")
+		}
+	}
+
+	close(in)
+	if got := collect(out); !reflect.DeepEqual(got, []int64{20}) {
+		t.Fatalf("Expected remaining [20], got %v", got)
+	}
+}
+
+// TestReorder_LateItems tests that items later than the window are forwarded immediately.
+func TestReorder_LateItems(t *testing.T) {
+	in := feed[int64](10, 20, 30, 5, 25)
+	got := collect(Reorder(in, identity, 5*time.Nanosecond))
+
+	// 10 and 20 are emitted once 30 arrives; 5 is too late and passes straight through;
+	// 25 is still within the window and is sorted before 30.
+	want := []int64{10, 20, 5, 25, 30}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,43 @@
+package stream
+
+// Result holds either a value or an error. It lets pipeline stages carry per-item failures down a
+// channel so that a terminal handler can deal with them, instead of aborting the whole stream.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Ok returns a successful Result holding value.
+func Ok[T any](value T) Result[T] {
+	return Result[T]{Value: value}
+}
+
+// Fail returns a failed Result holding err.
+func Fail[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+// Unwrap returns the value and error held by the Result.
+func (r Result[T]) Unwrap() (T, error) {
+	return r.Value, r.Err
+}
+
+// MapResult applies f to the value of every successful Result from in and emits the outcome. Failed
+// Results are short-circuited: their error is passed through unchanged and f is not called. The
+// returned channel is closed once in is closed.
+func MapResult[A, B any](in <-chan Result[A], f func(A) (B, error)) <-chan Result[B] {
+	out := make(chan Result[B])
+	go func() {
+		defer close(out)
+
+		for r := range in {
+			if r.Err != nil {
+				out <- Fail[B](r.Err)
+				continue
+			}
+			v, err := f(r.Value)
+			out <- Result[B]{Value: v, Err: err}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// TestMapResult tests that successful items are mapped and errors are produced per item.
+func TestMapResult(t *testing.T) {
+	in := feed(Ok("1"), Ok("x"), Ok("3"))
+	got := collect(MapResult(in, strconv.Atoi))
+
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(got))
+	}
+	if v, err := got[0].Unwrap(); err != nil || v != 1 {
+		t.Errorf("Expected 1, nil; got %v, %v", v, err)
+	}
+	if got[1].Err == nil {
+		t.Error("Expected parse error for \"x\"")
+	}
+	if v, err := got[2].Unwrap(); err != nil || v != 3 {
+		t.Errorf("Expected 3, nil; got %v, %v", v, err)
+	}
+}
+
+// TestMapResult_ErrorPropagation tests that an error flows unchanged through subsequent stages.
+func TestMapResult_ErrorPropagation(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+
+	stage1 := MapResult(feed(Ok(1), Ok(2), Ok(3)), func(v int) (int, error) {
+		if v == 2 {
+			return 0, errBoom
+		}
+		return v * 10, nil
+	})
+	stage2 := MapResult(stage1, func(v int) (string, error) {
+		calls++
+		return strconv.Itoa(v), nil
+	})
+	got := collect(stage2)
+
+	if calls != 2 {
+		t.Errorf("Expected second stage to be called for 2 items, got %d", calls)
+	}
+	if !errors.Is(got[1].Err, errBoom) {
+		t.Errorf("Expected errBoom to pass through, got %v", got[1].Err)
+	}
+	if got[0].Value != "10" || got[2].Value != "30" {
+		t.Errorf("Unexpected values: %q, %q", got[0].Value, got[2].Value)
+	}
+}
+
+// TestMapResult_FailedInput tests that failed inputs are passed through without calling f.
+func TestMapResult_FailedInput(t *testing.T) {
+	errIn := errors.New("upstream")
+	got := collect(MapResult(feed(Fail[int](errIn)), func(v int) (int, error) {
+		t.Fatal("f must not be called for failed results")
+		return v, nil
+	}))
+
+	if len(got) != 1 || !errors.Is(got[0].Err, errIn) {
+		t.Fatalf("Expected the upstream error, got %v", got)
+	}
+}
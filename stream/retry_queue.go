@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edast/go-utils/internal/random"
+)
+
+// RetryQueue is a work queue for processing that can fail transiently. A consumer takes items with
+// Take and reports a failed item with Fail, which puts it back on the queue after a jittered
+// exponential backoff delay. Once an item has failed maxAttempts times it is sent to a dead-letter channel
+// instead. Attempts are counted per item value, so items must be comparable and a value should not be
+// queued twice at once. It is safe for concurrent use by multiple goroutines.
+type RetryQueue[T comparable] struct {
+	ready       *Deque[T]     // Items available to Take, in order.
+	avail       chan struct{} // Signals waiting consumers that ready may be non-empty.
+	attempts    map[T]int     // Failures so far per item; removed by Done or on dead-lettering.
+	maxAttempts int           // Failures after which an item is dead-lettered.
+	base        time.Duration // Delay before the first retry.
+	max         time.Duration // Upper bound on the retry delay.
+	rand        random.Source // Source of backoff jitter.
+	dead        chan<- T      // Receives items that exhausted their attempts.
+	retries     *Scheduler    // Re-queues failed items once their delay has elapsed.
+	mu          sync.Mutex    // Mutex to protect attempts.
+}
+
+// NewRetryQueue creates a new RetryQueue. The delay before retrying a failed item is capped at base
+// for the first failure and at twice the previous cap for each further failure, up to max; within
+// the cap it is chosen uniformly at random ("full jitter"), so that items failing together do not
+// retry in lockstep. After maxAttempts failures an item is sent to dead.
+// Close must be called to release the queue's background goroutine.
+func NewRetryQueue[T comparable](maxAttempts int, base, max time.Duration, dead chan<- T) *RetryQueue[T] {
+	if maxAttempts <= 0 {
+		panic("stream: maxAttempts must be greater than zero")
+	}
+	if base <= 0 || max < base {
+		panic("stream: base delay must be greater than zero and not exceed max")
+	}
+	if dead == nil {
+		panic("stream: dead-letter channel must not be nil")
+	}
+
+	return &RetryQueue[T]{
+		ready:       NewDeque[T](),
+		avail:       make(chan struct{}, 1),
+		attempts:    make(map[T]int),
+		maxAttempts: maxAttempts,
+		base:        base,
+		max:         max,
+		rand:        random.Default,
+		dead:        dead,
+		retries:     NewScheduler(),
+	}
+}
+
+// Add queues item for processing.
+func (q *RetryQueue[T]) Add(item T) {
+	q.ready.PushBack(item)
+	q.signal()
+}
+
+// Take removes and returns the next available item, blocking until one is available. It returns
+// ctx.Err() if ctx is done first.
+func (q *RetryQueue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		if item, ok := q.ready.PopFront(); ok {
+			if q.ready.Len() > 0 {
+				q.signal() // Pass the wake-up on to another waiting consumer.
+			}
+			return item, nil
+		}
+		select {
+		case <-q.avail:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Fail records a failed attempt at item. If item has attempts left, it is queued again once its
+// backoff delay has elapsed; otherwise it is sent to the dead-letter channel, and Fail blocks until
+// the channel accepts it.
+func (q *RetryQueue[T]) Fail(item T) {
+	q.mu.Lock()
+	q.attempts[item]++
+	n := q.attempts[item]
+	if n >= q.maxAttempts {
+		delete(q.attempts, item)
+	}
+	q.mu.Unlock()
+
+	if n >= q.maxAttempts {
+		q.dead <- item
+		return
+	}
+	q.retries.Schedule(time.Now().Add(q.delay(n)), func() { q.Add(item) })
+}
+
+// Done forgets the attempts recorded for item, typically once it has been processed successfully.
+func (q *RetryQueue[T]) Done(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.attempts, item)
+}
+
+// Close stops the queue's background goroutine. Retries that have not been re-queued yet are
+// discarded.
+func (q *RetryQueue[T]) Close() {
+	q.retries.Stop()
+}
+
+// delay returns the backoff before the retry that follows the given number of failures.
+func (q *RetryQueue[T]) delay(failures int) time.Duration {
+	return random.Backoff(q.rand, failures-1, q.base, q.max)
+}
+
+// signal wakes a waiting consumer without blocking.
+func (q *RetryQueue[T]) signal() {
+	select {
+	case q.avail <- struct{}{}:
+	default: // A wake-up is already pending.
+	}
+}
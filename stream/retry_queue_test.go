@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edast/go-utils/internal/random"
+)
+
+// ceilingSource is a random.Source that always draws the largest value, so backoff delays hit their cap.
+type ceilingSource struct{}
+
+func (ceilingSource) Int63n(n int64) int64 { return n - 1 }
+
+// TestRetryQueue_AddTake tests that added items are taken in order.
+func TestRetryQueue_AddTake(t *testing.T) {
+	q := NewRetryQueue[string](3, time.Millisecond, time.Second, make(chan string, 1))
+	defer q.Close()
+
+	q.Add("a")
+	q.Add("b")
+	for _, want := range []string{"a", "b"} {
+		if got, err := q.Take(context.Background()); err != nil || got != want {
+			t.Fatalf("q.Take() = %q, %v; want %q, nil", got, err, want)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestRetryQueue_Delay tests that the backoff cap doubles per failure up to the maximum and that jittered delays repeat for the same seed.
+func TestRetryQueue_Delay(t *testing.T) {
+	q := NewRetryQueue[int](10, 10*time.Millisecond, 50*time.Millisecond, make(chan int))
+	defer q.Close()
+
+	caps := []time.Duration{10, 20, 40, 50, 50}
+	q.rand = ceilingSource{}
+	for i, c := range caps {
+		if got := q.delay(i + 1); got != c*time.Millisecond {
+			t.Errorf("Capped q.delay(%d) = %v; want %v", i+1, got, c*time.Millisecond)
+		}
+	}
+
+	q.rand = random.New(7)
+	want := random.New(7)
+	for i, c := range caps {
+		got := q.delay(i + 1)
+		if w := random.Backoff(want, i, 10*time.Millisecond, 50*time.Millisecond); got != w {
+			t.Errorf("q.delay(%d) = %v; want %v for the same seed", i+1, got, w)
+		}
+		if got < 0 || got > c*time.Millisecond {
+			t.Errorf("q.delay(%d) = %v; want within [0, %v]", i+1, got, c*time.Millisecond)
+		}
+	}
+}
+
+// TestRetryQueue_RetryThenDeadLetter tests that a failing item is retried with increasing delay and then dead-lettered.
+func TestRetryQueue_RetryThenDeadLetter(t *testing.T) {
+	dead := make(chan string, 1)
+	q := NewRetryQueue[string](3, 20*time.Millisecond, time.Second, dead)
+	defer q.Close()
+	q.rand = ceilingSource{} // Wait the whole backoff cap, so the waits grow.
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	q.Add("job")
+	item, _ := q.Take(ctx)
+
+	var waits []time.Duration
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		q.Fail(item)
+		var err error
+		if item, err = q.Take(ctx); err != nil {
+			t.Fatalf("Expected the item to be retried, got %v", err)
+		}
+		waits = append(waits, time.Since(start))
+	}
+	if waits[0] < 20*time.Millisecond || waits[1] < 40*time.Millisecond {
+		t.Fatalf("Expected retries after at least 20ms and 40ms, got %v", waits)
+	}
+
+	q.Fail(item) // Third failure exhausts the attempts.
+	select {
+	case got := <-dead:
+		if got != "job" {
+			t.Fatalf("Expected \"job\" to be dead-lettered, got %q", got)
+		}
+	default:
+		t.Fatal("Expected the item to be dead-lettered")
+	}
+	if n := q.ready.Len() + q.retries.Len(); n != 0 {
+		t.Fatalf("Expected nothing left to retry, got %d", n)
+	}
+}
+
+// TestRetryQueue_Done tests that Done resets the attempts of an item.
+func TestRetryQueue_Done(t *testing.T) {
+	dead := make(chan int, 1)
+	q := NewRetryQueue[int](2, time.Millisecond, time.Millisecond, dead)
+	defer q.Close()
+
+	q.Fail(1)
+	q.Done(1)
+	q.Fail(1) // Counts as the first failure again.
+
+	select {
+	case <-dead:
+		t.Fatal("Expected Done to reset the attempts")
+	default:
+	}
+}
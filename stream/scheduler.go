@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// task is a callback scheduled to run at a given time. index is its position in the taskHeap, or -1
+// once it has been removed.
+type task struct {
+	at    time.Time
+	fn    func()
+	index int
+}
+
+// taskHeap is a min-heap of tasks ordered by their deadline. It implements heap.Interface.
+type taskHeap []*task
+
+func (h taskHeap) Len() int           { return len(h) }
+func (h taskHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *taskHeap) Push(x any) {
+	t := x.(*task)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1 // Mark as removed
+	*h = old[:n-1]
+	return t
+}
+
+// Scheduler runs callbacks at scheduled times. A single background goroutine keeps the pending
+// callbacks in a min-heap of deadlines and sleeps on a timer that is re-armed whenever the earliest
+// deadline changes. Callbacks run sequentially on that goroutine, so long-running work should be
+// handed off to another goroutine.
+type Scheduler struct {
+	mu    sync.Mutex    // Mutex to protect the task heap.
+	tasks taskHeap      // Pending tasks, earliest deadline first.
+	wake  chan struct{} // Signals the run loop that the earliest deadline may have changed.
+	stop  chan struct{} // Closed to stop the run loop.
+	done  chan struct{} // Closed once the run loop has exited.
+	once  sync.Once     // Ensures Stop runs only once.
+}
+
+// NewScheduler creates a new Scheduler and starts its background goroutine.
+func NewScheduler() *Scheduler {
+	s := &Scheduler{
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Schedule arranges for fn to be called at time at, or as soon as possible if at is in the past.
+// The returned cancel function removes the callback if it has not run yet; calling it more than
+// once, or after the callback has run, has no effect.
+func (s *Scheduler) Schedule(at time.Time, fn func()) (cancel func()) {
+	t := &task{at: at, fn: fn}
+
+	s.mu.Lock()
+	heap.Push(&s.tasks, t)
+	earliest := t.index == 0
+	s.mu.Unlock()
+
+	if earliest {
+		s.notify()
+	}
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if t.index >= 0 {
+			heap.Remove(&s.tasks, t.index)
+		}
+	}
+}
+
+// Len returns the number of callbacks waiting to run.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tasks.Len()
+}
+
+// Stop stops the scheduler. Pending callbacks are discarded, and Stop waits for a callback that is
+// currently running to return. It is safe to call Stop more than once.
+func (s *Scheduler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+// notify wakes the run loop without blocking.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wake-up is already pending.
+	}
+}
+
+// run fires due callbacks and sleeps until the next deadline, a new earliest task, or Stop.
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		var next *task
+		wait := time.Hour // Arbitrary idle wait; Schedule wakes the loop early.
+		if s.tasks.Len() > 0 {
+			if wait = time.Until(s.tasks[0].at); wait <= 0 {
+				next = heap.Pop(&s.tasks).(*task)
+			}
+		}
+		s.mu.Unlock()
+
+		if next != nil {
+			next.fn()
+			continue
+		}
+
+		// Re-arm the timer, draining a stale expiry first.
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
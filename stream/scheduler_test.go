@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+// TestScheduler_FiresOnTime tests that a callback fires near its scheduled time.
+func TestScheduler_FiresOnTime(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	start := time.Now()
+	fired := make(chan time.Time, 1)
+	s.Schedule(start.Add(50*time.Millisecond), func() { fired <- time.Now() })
+
+	select {
+	case at := <-fired:
+		if elapsed := at.Sub(start); elapsed < 50*time.Millisecond || elapsed > 500*time.Millisecond {
+			t.Errorf("Expected callback after ~50ms, fired after %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for callback")
+	}
+}
+
+// TestScheduler_EarlierPreempts tests that scheduling an earlier callback re-arms the timer.
+func TestScheduler_EarlierPreempts(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	order := make(chan int, 2)
+	now := time.Now()
+	s.Schedule(now.Add(300*time.Millisecond), func() { order <- 2 })
+	time.Sleep(10 * time.Millisecond) // Let the run loop arm its timer for the later deadline.
+	s.Schedule(now.Add(20*time.Millisecond), func() { order <- 1 })
+
+	select {
+	case first := <-order:
+		if first != 1 {
+			t.Fatalf("Expected the earlier callback to fire first, got %d", first)
+		}
+		if elapsed := time.Since(now); elapsed > 200*time.Millisecond {
+			t.Errorf("Expected earlier callback to preempt the timer, fired after %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for callback")
+	}
+
+	select {
+	case second := <-order:
+		if second != 2 {
+			t.Fatalf("Expected the later callback second, got %d", second)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for callback")
+	}
+}
+
+// TestScheduler_Cancel tests that cancel prevents the callback from firing and removes it from the heap.
+func TestScheduler_Cancel(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	fired := make(chan struct{}, 1)
+	cancel := s.Schedule(time.Now().Add(30*time.Millisecond), func() { fired <- struct{}{} })
+	cancel()
+	cancel() // Canceling twice is a no-op.
+
+	if n := s.Len(); n != 0 {
+		t.Fatalf("Expected 0 pending callbacks after cancel, got %d", n)
+	}
+
+	select {
+	case <-fired:
+		t.Fatal("Canceled callback fired")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestScheduler_PastDeadline tests that a callback scheduled in the past runs immediately.
+func TestScheduler_PastDeadline(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	fired := make(chan struct{})
+	s.Schedule(time.Now().Add(-time.Second), func() { close(fired) })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for callback")
+	}
+}
@@ -0,0 +1,47 @@
+package stream
+
+import "context"
+
+// StageGroup manages the lifecycle of the goroutines that make up a pipeline. Stages started with Go
+// share a root context, and Shutdown cancels that context and waits for every stage to return, so a
+// pipeline can be torn down in one call.
+type StageGroup struct {
+	ctx     context.Context    // Root context passed to every stage.
+	cancel  context.CancelFunc // Cancels ctx.
+	running *Barrier           // Counts stages that have not returned yet.
+}
+
+// NewStageGroup creates a new StageGroup whose root context is derived from parent. Cancelling parent
+// stops the stages as well.
+func NewStageGroup(parent context.Context) *StageGroup {
+	ctx, cancel := context.WithCancel(parent)
+	return &StageGroup{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: NewBarrier(),
+	}
+}
+
+// Context returns the group's root context, which is done once Shutdown is called or the parent
+// context is cancelled.
+func (g *StageGroup) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs stage in a new goroutine, passing it the group's root context. A stage must return promptly
+// once that context is done.
+func (g *StageGroup) Go(stage func(ctx context.Context)) {
+	g.running.Add(1)
+	go func() {
+		defer g.running.Done()
+		stage(g.ctx)
+	}()
+}
+
+// Shutdown cancels the group's root context and waits for all stages to return. It returns nil once
+// they have, or ctx.Err() if ctx is done first, in which case the stages that are still running are
+// left to finish on their own.
+func (g *StageGroup) Shutdown(ctx context.Context) error {
+	g.cancel()
+	return g.running.Wait(ctx)
+}
@@ -0,0 +1,68 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestStageGroup_Shutdown tests that Shutdown stops a running multi-stage pipeline.
+func TestStageGroup_Shutdown(t *testing.T) {
+	g := NewStageGroup(context.Background())
+	numbers := make(chan int)
+	doubled := make(chan int)
+
+	g.Go(func(ctx context.Context) {
+		defer close(numbers)
+		for i := 0; ; i++ {
+			select {
+			case numbers <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	g.Go(func(ctx context.Context) {
+		defer close(doubled)
+		for n := range numbers {
+			select {
+			case doubled <- 2 * n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	g.Go(func(ctx context.Context) {
+		for range doubled {
+		}
+	})
+
+	time.Sleep(10 * time.Millisecond) // Let the pipeline run.
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected nil, got %v", err)
+	}
+	if g.Context().Err() == nil {
+		t.Fatal("Expected the root context to be cancelled")
+	}
+}
+
+// TestStageGroup_ShutdownTimeout tests that Shutdown reports a timeout when a stage hangs.
+func TestStageGroup_ShutdownTimeout(t *testing.T) {
+	g := NewStageGroup(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+
+	g.Go(func(ctx context.Context) {
+		<-release // Ignores ctx.
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}